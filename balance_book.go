@@ -0,0 +1,225 @@
+package poloniex
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WalletBalance is a balance of a currency in a wallet.
+type WalletBalance struct {
+	Currency   string
+	CurrencyID string
+	Wallet     Wallet
+	Available  float64
+	OnOrders   float64 // exchange wallet only, as of the last seed
+	UpdatedAt  time.Time
+}
+
+// BalanceChange is a balance after "b" account notification.
+type BalanceChange struct {
+	WalletBalance
+	Delta float64
+}
+
+type balanceKey struct {
+	currencyID string
+	wallet     Wallet
+}
+
+// BalanceBook keeps balances of all wallets, seeded from REST API and updated by "b" account notifications.
+type BalanceBook struct {
+	balances    map[balanceKey]*WalletBalance
+	currencies  map[string]string // currency by id
+	currencyIDs map[string]string // id by currency
+	changes     chan BalanceChange
+	dropped     uint64 // changes not sent because the channel was full
+	mu          sync.RWMutex
+}
+
+// NewBalanceBook creates empty balance book.
+func NewBalanceBook() *BalanceBook {
+	return &BalanceBook{
+		balances:    make(map[balanceKey]*WalletBalance),
+		currencies:  make(map[string]string),
+		currencyIDs: make(map[string]string),
+		changes:     make(chan BalanceChange, SUBSBUFFER),
+	}
+}
+
+// TrackBalances subscribes to account notification and feeds them into book.
+// Subscribe before Seed, so updates are not lost between the snapshot and the subscription.
+// The returned function stops tracking.
+func (ws *WSClient) TrackBalances(book *BalanceBook) (cancel func(), err error) {
+	if _, ok := ws.Subs["ACCOUNT"]; !ok {
+		if err = ws.SubscribeAccount(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ws.OnAccountUpdate(book.Apply), nil
+}
+
+// Seed loads currencies and replaces balances with the ones returned by the server.
+func (b *BalanceBook) Seed(p *Poloniex) error {
+	currencies, err := p.GetCurrencies()
+	if err != nil {
+		return err
+	}
+
+	accounts, err := p.GetAccountBalances()
+	if err != nil {
+		return err
+	}
+
+	complete, err := p.GetCompleteBalances()
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for currency, info := range currencies {
+		id := strconv.Itoa(info.ID)
+		b.currencies[id] = currency
+		b.currencyIDs[currency] = id
+	}
+
+	b.balances = make(map[balanceKey]*WalletBalance)
+
+	now := time.Now()
+	wallets := map[Wallet]map[string]decimal.Decimal{
+		WalletExchange: accounts.Exchange,
+		WalletMargin:   accounts.Margin,
+		WalletLending:  accounts.Lending,
+	}
+	for wallet, balances := range wallets {
+		for currency, amount := range balances {
+			balance := b.balance(b.currencyIDs[currency], currency, wallet)
+			balance.Available, _ = amount.Float64()
+			balance.UpdatedAt = now
+		}
+	}
+
+	// complete balances have on orders amounts of the exchange wallet
+	for currency, complete := range complete {
+		onOrders, _ := complete.OnOrders.Float64()
+		available, _ := complete.Available.Float64()
+		if onOrders == 0 && available == 0 {
+			continue
+		}
+
+		balance := b.balance(b.currencyIDs[currency], currency, WalletExchange)
+		balance.Available = available
+		balance.OnOrders = onOrders
+		balance.UpdatedAt = now
+	}
+
+	return nil
+}
+
+// Apply updates balances with "b" account notifications.
+// Available balance is taken from the notification, the delta is reported in the change.
+func (b *BalanceBook) Apply(updates []AccountUpdate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, update := range updates {
+		data, ok := update.Data.(BalanceUpdate)
+		if !ok {
+			continue
+		}
+
+		balance := b.balance(data.CurrencyID, b.currencies[data.CurrencyID], data.Wallet)
+		balance.Available = data.Balance
+		balance.UpdatedAt = time.Now()
+
+		select {
+		case b.changes <- BalanceChange{WalletBalance: *balance, Delta: data.Amount}:
+		default:
+			b.dropped++
+		}
+	}
+}
+
+// Changes returns the stream of balance changes. Balances are updated even if
+// the stream is full, the skipped changes are counted by Dropped.
+func (b *BalanceBook) Changes() <-chan BalanceChange {
+	return b.changes
+}
+
+// Dropped returns the number of balance changes not sent to the full Changes channel.
+func (b *BalanceBook) Dropped() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.dropped
+}
+
+// Balance returns balance of the currency in the wallet.
+func (b *BalanceBook) Balance(currency string, wallet Wallet) (WalletBalance, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	id, ok := b.currencyIDs[currency]
+	if !ok {
+		id = currency
+	}
+
+	balance, ok := b.balances[balanceKey{currencyID: id, wallet: wallet}]
+	if !ok {
+		return WalletBalance{}, false
+	}
+
+	return *balance, true
+}
+
+// Snapshot returns all balances.
+func (b *BalanceBook) Snapshot() []WalletBalance {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	balances := make([]WalletBalance, 0, len(b.balances))
+	for _, balance := range b.balances {
+		balances = append(balances, *balance)
+	}
+
+	return balances
+}
+
+// Currency returns currency by id sent in account notifications.
+func (b *BalanceBook) Currency(currencyID string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	currency, ok := b.currencies[currencyID]
+	return currency, ok
+}
+
+// Returns balance, registering it if it is seen for the first time.
+// Currencies without known id are keyed by name.
+func (b *BalanceBook) balance(currencyID, currency string, wallet Wallet) *WalletBalance {
+	key := balanceKey{currencyID: currencyID, wallet: wallet}
+	if currencyID == "" {
+		key.currencyID = currency
+	}
+
+	balance, ok := b.balances[key]
+	if !ok {
+		balance = &WalletBalance{
+			Currency:   currency,
+			CurrencyID: currencyID,
+			Wallet:     wallet,
+		}
+		b.balances[key] = balance
+	}
+
+	if balance.Currency == "" {
+		balance.Currency = currency
+	}
+
+	return balance
+}
@@ -0,0 +1,288 @@
+package poloniex
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CandleBuilder aggregates trades into OHLCV candles of arbitrary interval.
+// Candles are aligned to Unix epoch like the candles returned by GetChartData,
+// intervals without trades produce flat candles with zero volume.
+type CandleBuilder struct {
+	interval    time.Duration
+	historySize int
+	history     []CandleStick
+	current     *CandleStick
+	mu          sync.Mutex
+}
+
+// NewCandleBuilder creates builder of candles of interval,
+// keeping at most historySize closed candles (unlimited if zero).
+func NewCandleBuilder(interval time.Duration, historySize int) *CandleBuilder {
+	return &CandleBuilder{
+		interval:    interval,
+		historySize: historySize,
+	}
+}
+
+// Seed sets historical candles of builder interval, the last one becomes the current candle.
+func (b *CandleBuilder) Seed(candles []CandleStick) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.history = b.history[:0]
+	b.current = nil
+
+	if len(candles) == 0 {
+		return
+	}
+
+	for _, candle := range candles[:len(candles)-1] {
+		b.push(candle)
+	}
+
+	last := candles[len(candles)-1]
+	b.current = &last
+}
+
+// SeedFromChartData seeds builder with candles since start.
+// Candles are downloaded with the longest server period which divides builder interval and aggregated.
+// Intervals which no server period divides, e.g. 1m, are built from public trade history instead.
+func (b *CandleBuilder) SeedFromChartData(p *Poloniex, market string, start time.Time) error {
+	period, ok := chartPeriodFor(b.interval)
+	if !ok {
+		return b.seedFromTrades(p, market, start)
+	}
+
+	candles, err := p.GetChartData(market, start, time.Now(), period)
+	if err != nil {
+		return err
+	}
+
+	b.Seed(AggregateCandles(candles, b.interval))
+	return nil
+}
+
+// Seed builder with candles built from public trades since start.
+func (b *CandleBuilder) seedFromTrades(p *Poloniex, market string, start time.Time) error {
+	b.Seed(nil)
+
+	now := time.Now()
+	it := p.IteratePublicTradeHistory(market, start, now, TradeHistoryOptions{Forward: true})
+	for it.Next() {
+		trade := it.Trade()
+
+		date, err := parseStringToTime(trade.Date)
+		if err != nil {
+			return err
+		}
+
+		tradeID, _ := strconv.ParseInt(trade.TradeID, 10, 64)
+		side, _ := ParseSide(trade.Type)
+		rate, _ := trade.Price.Float64()
+		amount, _ := trade.Amount.Float64()
+		total, _ := trade.Total.Float64()
+
+		b.AddTrade(NewTrade{
+			TradeID:   tradeID,
+			Rate:      rate,
+			Amount:    amount,
+			Total:     total,
+			TypeOrder: side,
+			Date:      date,
+		})
+	}
+
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	b.Flush(now)
+	return nil
+}
+
+// AddTrade adds trade to the current candle and returns candles closed by the trade.
+// Trades older than the current candle are ignored.
+func (b *CandleBuilder) AddTrade(trade NewTrade) (closed []CandleStick) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	start := b.bucket(trade.Date)
+
+	if b.current != nil && start < b.current.Date {
+		return nil
+	}
+
+	closed = b.closeUntil(start)
+
+	if b.current == nil {
+		b.current = &CandleStick{Date: start}
+	}
+
+	c := b.current
+	if c.QuoteVolume == 0 {
+		// empty candle gets the price of the first trade
+		c.Open, c.High, c.Low = trade.Rate, trade.Rate, trade.Rate
+	}
+	c.High = math.Max(c.High, trade.Rate)
+	c.Low = math.Min(c.Low, trade.Rate)
+	c.Close = trade.Rate
+	c.Volume += trade.Rate * trade.Amount
+	c.QuoteVolume += trade.Amount
+	c.WeightedAverage = c.Volume / c.QuoteVolume
+
+	return closed
+}
+
+// Flush closes the current candle and flat candles up to now, if their intervals are over.
+func (b *CandleBuilder) Flush(now time.Time) (closed []CandleStick) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.closeUntil(b.bucket(now))
+}
+
+// Current returns the candle being built.
+func (b *CandleBuilder) Current() (CandleStick, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.current == nil {
+		return CandleStick{}, false
+	}
+
+	return *b.current, true
+}
+
+// Candles returns closed candles.
+func (b *CandleBuilder) Candles() []CandleStick {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	candles := make([]CandleStick, len(b.history))
+	copy(candles, b.history)
+
+	return candles
+}
+
+// Run consumes market updates, e.g. ws.Subs["USDT_BTC"] after SubscribeMarket,
+// and sends closed candles to the returned channel until updates channel is closed.
+func (b *CandleBuilder) Run(updates <-chan interface{}) <-chan CandleStick {
+	ch := make(chan CandleStick, SUBSBUFFER)
+
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			var closed []CandleStick
+
+			select {
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+
+				marketUpdates, ok := update.([]MarketUpdate)
+				if !ok {
+					continue
+				}
+
+				for _, msg := range marketUpdates {
+					if trade, ok := msg.Data.(NewTrade); ok {
+						closed = append(closed, b.AddTrade(trade)...)
+					}
+				}
+			case now := <-ticker.C:
+				closed = b.Flush(now)
+			}
+
+			for _, candle := range closed {
+				ch <- candle
+			}
+		}
+	}()
+
+	return ch
+}
+
+// Returns candle length in seconds.
+func (b *CandleBuilder) step() int64 {
+	if sec := int64(b.interval / time.Second); sec > 0 {
+		return sec
+	}
+
+	return 1
+}
+
+// Returns start of the interval containing t in Unix seconds.
+func (b *CandleBuilder) bucket(t time.Time) int64 {
+	return t.Unix() - t.Unix()%b.step()
+}
+
+// Close current candle and flat candles until the candle starting at start.
+func (b *CandleBuilder) closeUntil(start int64) (closed []CandleStick) {
+	if b.current == nil {
+		return nil
+	}
+
+	step := b.step()
+	for b.current.Date < start {
+		closed = append(closed, *b.current)
+		b.push(*b.current)
+
+		price := b.current.Close
+		b.current = &CandleStick{
+			Date:  b.current.Date + step,
+			Open:  price,
+			High:  price,
+			Low:   price,
+			Close: price,
+		}
+	}
+
+	return closed
+}
+
+func (b *CandleBuilder) push(candle CandleStick) {
+	b.history = append(b.history, candle)
+	if b.historySize > 0 && len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+}
+
+// AggregateCandles merges candles into candles of longer interval aligned to Unix epoch.
+// Source candles must be sorted by date.
+func AggregateCandles(candles []CandleStick, interval time.Duration) []CandleStick {
+	sec := int64(interval / time.Second)
+	if sec <= 0 {
+		return nil
+	}
+
+	var res []CandleStick
+	for _, c := range candles {
+		start := c.Date - c.Date%sec
+
+		if len(res) == 0 || res[len(res)-1].Date != start {
+			c.Date = start
+			res = append(res, c)
+			continue
+		}
+
+		last := &res[len(res)-1]
+		last.High = math.Max(last.High, c.High)
+		last.Low = math.Min(last.Low, c.Low)
+		last.Close = c.Close
+		last.Volume += c.Volume
+		last.QuoteVolume += c.QuoteVolume
+		if last.QuoteVolume > 0 {
+			last.WeightedAverage = last.Volume / last.QuoteVolume
+		}
+	}
+
+	return res
+}
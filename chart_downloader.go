@@ -0,0 +1,221 @@
+package poloniex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultChunkCandles is a number of candles requested at once.
+const defaultChunkCandles = 5000
+
+// ChartGap is a range of missing candles [Start, End).
+type ChartGap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ChartDownloader downloads chart data over long ranges.
+// The range is split into chunks aligned to Unix epoch, so completed chunks
+// can be cached on disk and re-runs only fetch new candles.
+type ChartDownloader struct {
+	client       *Poloniex
+	CacheDir     string // directory for completed chunks, caching is disabled if empty
+	FillGaps     bool   // fill missing candles with flat candles
+	ChunkCandles int    // candles per request
+}
+
+// NewChartDownloader creates downloader, cacheDir is optional.
+func NewChartDownloader(client *Poloniex, cacheDir string) *ChartDownloader {
+	return &ChartDownloader{
+		client:       client,
+		CacheDir:     cacheDir,
+		ChunkCandles: defaultChunkCandles,
+	}
+}
+
+// Download returns candles within [start, end] and gaps in the range, including missing
+// candles at its start and end.
+// Filled gaps are reported as well.
+func (d *ChartDownloader) Download(market string, start, end time.Time,
+	period string) (candles []CandleStick, gaps []ChartGap, err error) {
+
+	periodSec, ok := chartPeriods[period]
+	if !ok {
+		return nil, nil, Error(PeriodError)
+	}
+
+	if !start.Before(end) {
+		return nil, nil, Error(TimeError)
+	}
+
+	market = strings.ToUpper(market)
+
+	chunkCandles := d.ChunkCandles
+	if chunkCandles < 2 {
+		chunkCandles = defaultChunkCandles
+	}
+
+	step := int64(periodSec)
+	span := step * int64(chunkCandles)
+	from, to := start.Unix(), end.Unix()
+
+	byDate := make(map[int64]CandleStick)
+	for chunk := from - from%span; chunk <= to; chunk += span {
+		part, err := d.chunk(market, period, chunk, chunk+span-step)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, candle := range part {
+			if candle.Date >= from && candle.Date <= to {
+				byDate[candle.Date] = candle
+			}
+		}
+	}
+
+	candles = make([]CandleStick, 0, len(byDate))
+	for _, candle := range byDate {
+		candles = append(candles, candle)
+	}
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Date < candles[j].Date
+	})
+
+	candles, gaps = d.findGaps(candles, from, to, step)
+	return candles, gaps, nil
+}
+
+// Load chunk from cache or server. Completed chunks are cached.
+func (d *ChartDownloader) chunk(market, period string, start, end int64) ([]CandleStick, error) {
+	var candles []CandleStick
+
+	cachePath := ""
+	if d.CacheDir != "" {
+		cachePath = filepath.Join(d.CacheDir, fmt.Sprintf("%s_%s_%d_%d.json", market, period, start, end))
+
+		if data, err := ioutil.ReadFile(cachePath); err == nil {
+			if err = json.Unmarshal(data, &candles); err == nil {
+				return candles, nil
+			}
+		}
+	}
+
+	candles, err := d.client.GetChartData(market, time.Unix(start, 0), time.Unix(end, 0), period)
+	if err != nil {
+		return nil, err
+	}
+
+	// server sends a single zero candle when there is no data
+	valid := candles[:0]
+	for _, candle := range candles {
+		if candle.Date != 0 {
+			valid = append(valid, candle)
+		}
+	}
+	candles = valid
+
+	periodSec := int64(chartPeriods[period])
+	if cachePath != "" && end+periodSec <= time.Now().Unix() {
+		if err := writeFileAtomic(cachePath, candles); err != nil {
+			logger.WithError(err).Error("can not cache chart data")
+		}
+	}
+
+	return candles, nil
+}
+
+// Detect missing candles within [from, to] and fill them if needed.
+// Candles missing before the first one are filled with its open price,
+// the rest with the close price of the previous candle. Empty range can not be filled.
+func (d *ChartDownloader) findGaps(candles []CandleStick, from, to, step int64) ([]CandleStick, []ChartGap) {
+	// candles are not expected after the current time
+	if now := time.Now().Unix(); to > now {
+		to = now
+	}
+
+	first := from + (step-from%step)%step
+	last := to - to%step
+
+	var gaps []ChartGap
+	filled := make([]CandleStick, 0, len(candles))
+
+	next := first
+	fill := func(end int64, price float64, ok bool) {
+		if end <= next {
+			return
+		}
+
+		gaps = append(gaps, ChartGap{
+			Start: time.Unix(next, 0).UTC(),
+			End:   time.Unix(end, 0).UTC(),
+		})
+
+		if d.FillGaps && ok {
+			for date := next; date < end; date += step {
+				filled = append(filled, CandleStick{
+					Date:            date,
+					High:            price,
+					Low:             price,
+					Open:            price,
+					Close:           price,
+					WeightedAverage: price,
+				})
+			}
+		}
+	}
+
+	for i, candle := range candles {
+		if i == 0 {
+			fill(candle.Date, candle.Open, true)
+		} else {
+			fill(candle.Date, candles[i-1].Close, true)
+		}
+
+		filled = append(filled, candle)
+		next = candle.Date + step
+	}
+
+	if len(candles) == 0 {
+		fill(last+step, 0, false)
+	} else {
+		fill(last+step, candles[len(candles)-1].Close, true)
+	}
+
+	return filled, gaps
+}
+
+// Write value as JSON into file through temporary file, so readers never see partial data.
+func writeFileAtomic(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -36,6 +37,11 @@ type Poloniex struct {
 	secret     string
 	httpClient *http.Client
 	observer   OrderObserver
+
+	withdrawalAddresses map[string]map[string]struct{} // allowed withdrawal addresses by currency
+	withdrawalMu        sync.RWMutex
+
+	markets *MarketInfoCache // validates orders before they are sent if set
 }
 
 func NewPublicClient() *Poloniex {
@@ -44,7 +50,12 @@ func NewPublicClient() *Poloniex {
 	}
 }
 
+// NewPrivateClient creates new private client, nil observer means NilObserver.
 func NewPrivateClient(observer OrderObserver, key, secret string) *Poloniex {
+	if observer == nil {
+		observer = NewNilObserver()
+	}
+
 	return &Poloniex{
 		key:        key,
 		secret:     secret,
@@ -168,6 +179,7 @@ func (p *Poloniex) tradingRequest(action string, parameters map[string]string,
 	if err != nil {
 		respCh <- nil
 		errCh <- err
+		return
 	}
 
 	respCh <- body
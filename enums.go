@@ -0,0 +1,261 @@
+package poloniex
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Side is a side of an order or a trade.
+type Side int
+
+// List of sides.
+const (
+	SideBuy Side = iota + 1
+	SideSell
+)
+
+var sideNames = []string{"unknown", OrderTypeBuyValue, OrderTypeSellValue}
+
+// ParseSide converts "buy" or "sell" to Side.
+func ParseSide(s string) (Side, error) {
+	v, err := parseEnum(sideNames, s)
+	return Side(v), err
+}
+
+func (s Side) String() string {
+	return enumString(sideNames, int(s))
+}
+
+// MarshalJSON encodes Side as a string.
+func (s Side) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes Side from a string.
+func (s *Side) UnmarshalJSON(b []byte) error {
+	return unmarshalEnum(b, sideNames, (*int)(s))
+}
+
+func (s Side) isValid() bool {
+	return s == SideBuy || s == SideSell
+}
+
+// Wallet is an account which holds balances.
+type Wallet int
+
+// List of wallets.
+const (
+	WalletExchange Wallet = iota + 1
+	WalletMargin
+	WalletLending
+)
+
+var walletNames = []string{"unknown", "exchange", "margin", "lending"}
+
+func (w Wallet) String() string {
+	return enumString(walletNames, int(w))
+}
+
+// MarshalJSON encodes Wallet as a string.
+func (w Wallet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.String())
+}
+
+// UnmarshalJSON decodes Wallet from a string.
+func (w *Wallet) UnmarshalJSON(b []byte) error {
+	return unmarshalEnum(b, walletNames, (*int)(w))
+}
+
+func (w Wallet) isValid() bool {
+	return w == WalletExchange || w == WalletMargin || w == WalletLending
+}
+
+// FundingType is the funding used for a trade.
+type FundingType int
+
+// List of funding types, values match the ones sent by poloniex.
+const (
+	FundingTypeExchange FundingType = iota // exchange wallet
+	FundingTypeBorrowed                    // borrowed funds
+	FundingTypeMargin                      // margin funds
+	FundingTypeLending                     // lending funds
+)
+
+var fundingTypeNames = []string{"exchange", "borrowed", "margin", "lending"}
+
+func (f FundingType) String() string {
+	return enumString(fundingTypeNames, int(f))
+}
+
+// MarshalJSON encodes FundingType as a string.
+func (f FundingType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.String())
+}
+
+// UnmarshalJSON decodes FundingType from a string.
+func (f *FundingType) UnmarshalJSON(b []byte) error {
+	return unmarshalEnum(b, fundingTypeNames, (*int)(f))
+}
+
+// OrderUpdateKind is a reason of "o" order update.
+type OrderUpdateKind int
+
+// List of order update kinds.
+const (
+	OrderUpdateFill OrderUpdateKind = iota + 1
+	OrderUpdateSelfTrade
+	OrderUpdateCanceled
+)
+
+var orderUpdateKindNames = []string{"unknown", "fill", "self-trade", "canceled"}
+
+func (k OrderUpdateKind) String() string {
+	return enumString(orderUpdateKindNames, int(k))
+}
+
+// MarshalJSON encodes OrderUpdateKind as a string.
+func (k OrderUpdateKind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+// UnmarshalJSON decodes OrderUpdateKind from a string.
+func (k *OrderUpdateKind) UnmarshalJSON(b []byte) error {
+	return unmarshalEnum(b, orderUpdateKindNames, (*int)(k))
+}
+
+// Liquidity is a role of the order in a trade.
+type Liquidity int
+
+// List of liquidity roles.
+const (
+	LiquidityMaker Liquidity = iota + 1
+	LiquidityTaker
+)
+
+var liquidityNames = []string{"unknown", "maker", "taker"}
+
+func (l Liquidity) String() string {
+	return enumString(liquidityNames, int(l))
+}
+
+// MarshalJSON encodes Liquidity as a string.
+func (l Liquidity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+// UnmarshalJSON decodes Liquidity from a string.
+func (l *Liquidity) UnmarshalJSON(b []byte) error {
+	return unmarshalEnum(b, liquidityNames, (*int)(l))
+}
+
+// OrderStatus is a lifecycle state of an order built from account notifications.
+type OrderStatus int
+
+// List of order states.
+const (
+	OrderStatusPending OrderStatus = iota + 1
+	OrderStatusOpen
+	OrderStatusPartiallyFilled
+	OrderStatusFilled
+	OrderStatusCanceled
+	OrderStatusKilled
+	OrderStatusSelfTrade
+)
+
+var orderStatusNames = []string{
+	"unknown", "pending", "open", "partially filled", "filled", "canceled", "killed", "self-trade",
+}
+
+func (s OrderStatus) String() string {
+	return enumString(orderStatusNames, int(s))
+}
+
+// MarshalJSON encodes OrderStatus as a string.
+func (s OrderStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes OrderStatus from a string.
+func (s *OrderStatus) UnmarshalJSON(b []byte) error {
+	return unmarshalEnum(b, orderStatusNames, (*int)(s))
+}
+
+// IsFinal reports whether no more updates are expected for the order.
+func (s OrderStatus) IsFinal() bool {
+	switch s {
+	case OrderStatusFilled, OrderStatusCanceled, OrderStatusKilled, OrderStatusSelfTrade:
+		return true
+	default:
+		return false
+	}
+}
+
+// UpdateType is a type of account notification or market update.
+type UpdateType int
+
+// List of update types.
+const (
+	MessageTypePending UpdateType = iota + 1
+	MessageTypeOrderUpdate
+	MessageTypeTrade
+	MessageTypeBalance
+	MessageTypeNewOrder
+	MessageTypeMargin
+	MessageTypeKill
+	MessageTypeOrderDepth
+	MessageTypeOrderBookModify
+	MessageTypeOrderBookRemove
+	MessageTypeNewTrade
+)
+
+var updateTypeNames = []string{
+	"unknown", "Pending", "OrderUpdate", "Trade", "BalanceUpdate", "NewOrder", "MarginPositionUpdate", "Kill",
+	"OrderDepth", "OrderBookModify", "OrderBookRemove", "NewTrade",
+}
+
+func (u UpdateType) String() string {
+	return enumString(updateTypeNames, int(u))
+}
+
+// MarshalJSON encodes UpdateType as a string.
+func (u UpdateType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON decodes UpdateType from a string.
+func (u *UpdateType) UnmarshalJSON(b []byte) error {
+	return unmarshalEnum(b, updateTypeNames, (*int)(u))
+}
+
+func enumString(names []string, v int) string {
+	if v < 0 || v >= len(names) {
+		return "unknown"
+	}
+
+	return names[v]
+}
+
+func parseEnum(names []string, s string) (int, error) {
+	for i, name := range names {
+		if name == s && name != "unknown" {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown value: %v", s)
+}
+
+func unmarshalEnum(b []byte, names []string, v *int) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	parsed, err := parseEnum(names, s)
+	if err != nil {
+		return err
+	}
+
+	*v = parsed
+	return nil
+}
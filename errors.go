@@ -24,6 +24,14 @@ const (
 	WSAccountNotification = "[ERROR] Account Notification Parsing %s"
 	WSWrongOrderType      = "[ERROR] Account Notification Parsing: Wrong Order Type %s"
 	WrongTimeFormat       = "[ERROR] Wrong time format from Poloniex"
+	ClientOrderIDError    = "[ERROR] Invalid clientOrderId %s"
+	OrderRequestError     = "[ERROR] Invalid order request: %s"
+	WithdrawAddressError  = "[ERROR] Withdrawal address is not allowed: %s"
+	TransferError         = "[ERROR] Invalid transfer between wallets %s"
+	TransferNotConfirmed  = "[ERROR] Transfer balance updates not received for %s"
+	MarketRuleError       = "[ERROR] Order violates market rules: %s"
+	PaperTradeError       = "[ERROR] Paper trading: %s"
+	TradeHistoryError     = "[ERROR] Trade history: %s"
 	ServerError           = "[SERVER ERROR] Response: %s"
 )
 
@@ -0,0 +1,296 @@
+// Package export writes account activity as CSV or JSON Lines.
+//
+// Every record kind has a stable column schema, timestamps are written in RFC 3339 (UTC)
+// and decimal values are written as exact strings.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"vcshl.b2broker.tech/common/golang-libs/poloniex"
+)
+
+// Format is an output format.
+type Format int
+
+// List of formats.
+const (
+	CSV   Format = iota // comma separated values with header
+	JSONL               // one JSON object per line
+)
+
+// Column schemas.
+var (
+	TradeColumns = []string{
+		"date", "market", "global_trade_id", "trade_id", "order_number",
+		"type", "category", "price", "amount", "total", "fee_rate",
+	}
+	FeeColumns = []string{
+		"date", "market", "trade_id", "order_number", "type", "fee_rate", "fee", "fee_currency",
+	}
+	DepositColumns = []string{
+		"date", "currency", "amount", "address", "payment_id", "txid",
+		"confirmations", "status", "deposit_number",
+	}
+	WithdrawalColumns = []string{
+		"date", "currency", "amount", "fee", "address", "payment_id", "txid",
+		"status", "withdrawal_number",
+	}
+	BalanceColumns = []string{
+		"date", "currency", "available", "on_orders", "btc_value",
+	}
+)
+
+const poloniexTimeLayout = "2006-01-02 15:04:05"
+
+// Exporter writes records into w.
+type Exporter struct {
+	w      io.Writer
+	format Format
+}
+
+// New creates exporter writing in format.
+func New(w io.Writer, format Format) *Exporter {
+	return &Exporter{w: w, format: format}
+}
+
+// Trades writes account trades.
+func (e *Exporter) Trades(trades []poloniex.MarketTrade) error {
+	rows := make([][]string, 0, len(trades))
+	for i := range trades {
+		t := &trades[i]
+		date, err := formatDate(t.Date)
+		if err != nil {
+			return err
+		}
+
+		rows = append(rows, []string{
+			date,
+			t.Market,
+			strconv.Itoa(t.GlobalTradeID),
+			t.TradeID,
+			t.OrderNumber.String(),
+			t.Type,
+			t.Category,
+			t.Price.String(),
+			t.Amount.String(),
+			t.Total.String(),
+			t.Fee.String(),
+		})
+	}
+
+	return e.write(TradeColumns, rows)
+}
+
+// OrderTrades writes trades of the order returned by GetTradesByOrderID.
+func (e *Exporter) OrderTrades(orderNumber string, trades []poloniex.OrderTrade) error {
+	rows := make([][]string, 0, len(trades))
+	for i := range trades {
+		t := &trades[i]
+		date, err := formatDate(t.Date)
+		if err != nil {
+			return err
+		}
+
+		rows = append(rows, []string{
+			date,
+			t.Market,
+			t.GlobalTradeID.String(),
+			t.TradeID.String(),
+			orderNumber,
+			t.Type,
+			"",
+			t.Price.String(),
+			t.Amount.String(),
+			t.Total.String(),
+			t.Fee.String(),
+		})
+	}
+
+	return e.write(TradeColumns, rows)
+}
+
+// Fees writes fees paid for account trades.
+// Buy fee is charged in bought currency, sell fee in base currency of the market.
+func (e *Exporter) Fees(trades []poloniex.MarketTrade) error {
+	rows := make([][]string, 0, len(trades))
+	for i := range trades {
+		t := &trades[i]
+		date, err := formatDate(t.Date)
+		if err != nil {
+			return err
+		}
+
+		base, quote := splitMarket(t.Market)
+		fee, currency := t.Total.Mul(t.Fee), base
+		if t.Type == poloniex.OrderTypeBuyValue {
+			fee, currency = t.Amount.Mul(t.Fee), quote
+		}
+
+		rows = append(rows, []string{
+			date,
+			t.Market,
+			t.TradeID,
+			t.OrderNumber.String(),
+			t.Type,
+			t.Fee.String(),
+			fee.String(),
+			currency,
+		})
+	}
+
+	return e.write(FeeColumns, rows)
+}
+
+// Deposits writes deposits history.
+func (e *Exporter) Deposits(deposits []poloniex.Deposit) error {
+	rows := make([][]string, 0, len(deposits))
+	for i := range deposits {
+		d := &deposits[i]
+		rows = append(rows, []string{
+			formatUnix(d.Timestamp),
+			d.Currency,
+			d.Amount.String(),
+			d.Address,
+			d.PaymentID,
+			d.TxID,
+			strconv.Itoa(d.Confirmations),
+			d.Status,
+			strconv.FormatInt(d.DepositNumber, 10),
+		})
+	}
+
+	return e.write(DepositColumns, rows)
+}
+
+// Withdrawals writes withdrawals history.
+func (e *Exporter) Withdrawals(withdrawals []poloniex.Withdrawal) error {
+	rows := make([][]string, 0, len(withdrawals))
+	for i := range withdrawals {
+		w := &withdrawals[i]
+		rows = append(rows, []string{
+			formatUnix(w.Timestamp),
+			w.Currency,
+			w.Amount.String(),
+			w.Fee.String(),
+			w.Address,
+			w.PaymentID,
+			w.TxID,
+			w.Status,
+			strconv.FormatInt(w.WithdrawalNumber, 10),
+		})
+	}
+
+	return e.write(WithdrawalColumns, rows)
+}
+
+// Balances writes balances returned by GetCompleteBalances as of date, sorted by currency.
+func (e *Exporter) Balances(date time.Time, balances map[string]poloniex.Balance) error {
+	currencies := make([]string, 0, len(balances))
+	for currency := range balances {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	rows := make([][]string, 0, len(balances))
+	for _, currency := range currencies {
+		b := balances[currency]
+		rows = append(rows, []string{
+			date.UTC().Format(time.RFC3339),
+			currency,
+			b.Available.String(),
+			b.OnOrders.String(),
+			b.BtcValue.String(),
+		})
+	}
+
+	return e.write(BalanceColumns, rows)
+}
+
+func (e *Exporter) write(columns []string, rows [][]string) error {
+	switch e.format {
+	case CSV:
+		w := csv.NewWriter(e.w)
+		if err := w.Write(columns); err != nil {
+			return err
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return err
+		}
+		return w.Error()
+
+	case JSONL:
+		for _, row := range rows {
+			line, err := jsonLine(columns, row)
+			if err != nil {
+				return err
+			}
+			if _, err := e.w.Write(line); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return errors.New("unknown export format")
+	}
+}
+
+// Build JSON object keeping column order.
+func jsonLine(columns, row []string) ([]byte, error) {
+	var b strings.Builder
+
+	b.WriteByte('{')
+	for i, column := range columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(row[i])
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteString("}\n")
+
+	return []byte(b.String()), nil
+}
+
+// Convert poloniex date "2021-07-09 03:46:50" (UTC) into RFC 3339.
+func formatDate(date string) (string, error) {
+	t, err := time.Parse(poloniexTimeLayout, date)
+	if err != nil {
+		return "", err
+	}
+
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+func formatUnix(sec int64) string {
+	return time.Unix(sec, 0).UTC().Format(time.RFC3339)
+}
+
+// Split market like "BTC_ETH" into base and quote currencies.
+func splitMarket(market string) (base, quote string) {
+	parts := strings.SplitN(strings.ToUpper(market), "_", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
@@ -0,0 +1,160 @@
+package poloniex
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// Observed order as it is stored in the file.
+type savedOrder struct {
+	ObservedOrder
+	FinishedAt *time.Time `json:"finishedAt,omitempty"` // set for finished orders waiting for removal
+}
+
+// FileObserver is WebsocketObserver which keeps observed orders in a JSON file,
+// so fills of orders placed before a restart are still reported.
+// Changes are written by a background goroutine, Close writes pending changes and stops it.
+type FileObserver struct {
+	*WebsocketObserver
+	path      string
+	saveMu    sync.Mutex
+	dirty     chan struct{} // signals the saver about unsaved changes
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewFileObserver creates observer stored in path and restores orders saved there.
+// Missing file is not an error.
+func NewFileObserver(path string) (*FileObserver, error) {
+	f := &FileObserver{
+		WebsocketObserver: NewWebsocketObserver(),
+		path:              path,
+		dirty:             make(chan struct{}, 1),
+		done:              make(chan struct{}),
+		stopped:           make(chan struct{}),
+	}
+	f.onChange = f.scheduleSave
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		go f.saver()
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []savedOrder
+	if err = json.Unmarshal(data, &orders); err != nil {
+		return nil, err
+	}
+
+	for _, order := range orders {
+		f.items[order.OrderID] = order.ObservedOrder
+		if order.FinishedAt != nil {
+			f.finished.add(order.OrderID, *order.FinishedAt)
+		}
+	}
+	f.scheduleCleanup(time.Now())
+
+	go f.saver()
+	return f, nil
+}
+
+// Rehydrate observes open orders of the account which are not observed yet,
+// e.g. orders placed by another process or before the file was created.
+// Returns number of added orders.
+func (f *FileObserver) Rehydrate(p *Poloniex) (added int, err error) {
+	openOrders, err := p.GetAllOpenOrders()
+	if err != nil {
+		return 0, err
+	}
+
+	f.itemsMu.Lock()
+	for market, orders := range openOrders {
+		for _, order := range orders {
+			side, err := ParseSide(order.Type)
+			if err != nil {
+				continue
+			}
+
+			if _, ok := f.items[order.OrderNumber]; ok {
+				continue
+			}
+
+			f.items[order.OrderNumber] = ObservedOrder{
+				Side:    side,
+				Symbol:  market,
+				OrderID: order.OrderNumber,
+			}
+			added++
+		}
+	}
+	f.itemsMu.Unlock()
+
+	if added == 0 {
+		return 0, nil
+	}
+
+	return added, f.save()
+}
+
+// Close writes pending changes to the file and stops the background saver.
+func (f *FileObserver) Close() error {
+	f.closeOnce.Do(func() {
+		close(f.done)
+	})
+	<-f.stopped
+
+	return f.save()
+}
+
+// Signal the saver about changes, so the file is not written by the websocket reader.
+func (f *FileObserver) scheduleSave() error {
+	select {
+	case f.dirty <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Write the file after every change signal until Close.
+func (f *FileObserver) saver() {
+	defer close(f.stopped)
+
+	for {
+		select {
+		case <-f.done:
+			return
+		case <-f.dirty:
+		}
+
+		if err := f.save(); err != nil {
+			logger.WithError(err).Error("can not save observer")
+		}
+	}
+}
+
+// Write snapshot of observed orders and their finish times to the file.
+func (f *FileObserver) save() error {
+	f.saveMu.Lock()
+	defer f.saveMu.Unlock()
+
+	f.itemsMu.RLock()
+	orders := make([]savedOrder, 0, len(f.items))
+	for orderID, item := range f.items {
+		order := savedOrder{ObservedOrder: item}
+		if finishedAt, ok := f.finished[orderID]; ok {
+			order.FinishedAt = &finishedAt
+		}
+		orders = append(orders, order)
+	}
+	f.itemsMu.RUnlock()
+
+	return writeFileAtomic(f.path, orders)
+}
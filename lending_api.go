@@ -0,0 +1,238 @@
+package poloniex
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type CreateLoanOffer struct {
+	Success int    `json:"success"`
+	Message string `json:"message"`
+	OrderID int64  `json:"orderID"`
+}
+
+// CreateLoanOffer places a loan offer. duration is in days.
+func (p *Poloniex) CreateLoanOffer(currency string, amount, lendingRate decimal.Decimal,
+	duration int, autoRenew bool) (offer CreateLoanOffer, err error) {
+
+	parameters := map[string]string{
+		"currency":    strings.ToUpper(currency),
+		"amount":      amount.StringFixed(8),
+		"duration":    strconv.Itoa(duration),
+		"autoRenew":   "0",
+		"lendingRate": lendingRate.StringFixed(8),
+	}
+
+	if autoRenew {
+		parameters["autoRenew"] = "1"
+	}
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("createLoanOffer", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &offer)
+	return
+}
+
+type CancelLoanOffer struct {
+	Success int             `json:"success"`
+	Message string          `json:"message"`
+	Amount  decimal.Decimal `json:"amount"`
+}
+
+// CancelLoanOffer cancels the loan offer.
+func (p *Poloniex) CancelLoanOffer(orderNumber string) (cancelOffer CancelLoanOffer, err error) {
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	parameters := map[string]string{"orderNumber": orderNumber}
+	go p.tradingRequest("cancelLoanOffer", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &cancelOffer)
+	return
+}
+
+type LoanOffer struct {
+	ID        int64           `json:"id"`
+	Rate      decimal.Decimal `json:"rate"`
+	Amount    decimal.Decimal `json:"amount"`
+	Duration  int             `json:"duration"`
+	AutoRenew int             `json:"autoRenew"`
+	Date      string          `json:"date"`
+}
+
+// GetOpenLoanOffers returns open loan offers by currency.
+func (p *Poloniex) GetOpenLoanOffers() (offers map[string][]LoanOffer, err error) {
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("returnOpenLoanOffers", nil, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	offers = make(map[string][]LoanOffer)
+
+	// empty result is sent as array
+	if bytes.Equal(bytes.TrimSpace(resp), []byte("[]")) {
+		return
+	}
+
+	err = json.Unmarshal(resp, &offers)
+	return
+}
+
+type ActiveLoan struct {
+	ID        int64           `json:"id"`
+	Currency  string          `json:"currency"`
+	Rate      decimal.Decimal `json:"rate"`
+	Amount    decimal.Decimal `json:"amount"`
+	Duration  int             `json:"range"`
+	AutoRenew int             `json:"autoRenew"`
+	Date      string          `json:"date"`
+	Fees      decimal.Decimal `json:"fees"`
+}
+
+type ActiveLoans struct {
+	Provided []ActiveLoan `json:"provided"`
+	Used     []ActiveLoan `json:"used"`
+}
+
+// GetActiveLoans returns loans provided and used by the account.
+func (p *Poloniex) GetActiveLoans() (loans ActiveLoans, err error) {
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("returnActiveLoans", nil, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &loans)
+	return
+}
+
+type LendingHistory struct {
+	ID       int64           `json:"id"`
+	Currency string          `json:"currency"`
+	Rate     decimal.Decimal `json:"rate"`
+	Amount   decimal.Decimal `json:"amount"`
+	Duration decimal.Decimal `json:"duration"`
+	Interest decimal.Decimal `json:"interest"`
+	Fee      decimal.Decimal `json:"fee"`
+	Earned   decimal.Decimal `json:"earned"`
+	Open     string          `json:"open"`
+	Close    string          `json:"close"`
+}
+
+// GetLendingHistory returns closed loans within the time range.
+func (p *Poloniex) GetLendingHistory(start, end time.Time, limit int) (history []LendingHistory, err error) {
+	parameters := map[string]string{
+		"start": strconv.FormatInt(start.Unix(), 10),
+		"end":   strconv.FormatInt(end.Unix(), 10),
+	}
+
+	if limit > 0 {
+		parameters["limit"] = strconv.Itoa(limit)
+	}
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("returnLendingHistory", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &history)
+	return
+}
+
+type ToggleAutoRenew struct {
+	Success   int `json:"success"`
+	AutoRenew int `json:"message"` // new auto-renew state
+}
+
+// ToggleAutoRenew switches auto-renew of the active loan.
+func (p *Poloniex) ToggleAutoRenew(orderNumber string) (toggle ToggleAutoRenew, err error) {
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	parameters := map[string]string{"orderNumber": orderNumber}
+	go p.tradingRequest("toggleAutoRenew", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &toggle)
+	return
+}
+
+// OfferRate picks lending rate from the book for an offer of duration days.
+// The rate joins the cheapest level where at least depth amount is offered at lower or equal rates,
+// so depth controls how far from the top of the book the offer is placed.
+// It returns false if there are no offers for the duration.
+func (l LoanOrder) OfferRate(duration int, depth decimal.Decimal) (rate decimal.Decimal, ok bool) {
+	offers := make([]LoanOrderSc, 0, len(l.Offers))
+	for _, offer := range l.Offers {
+		if offer.RangeMin <= duration && duration <= offer.RangeMax {
+			offers = append(offers, offer)
+		}
+	}
+
+	if len(offers) == 0 {
+		return decimal.Zero, false
+	}
+
+	sort.Slice(offers, func(i, j int) bool {
+		return offers[i].Rate.LessThan(offers[j].Rate)
+	})
+
+	total := decimal.Zero
+	for _, offer := range offers {
+		total = total.Add(offer.Amount)
+		if total.GreaterThanOrEqual(depth) {
+			return offer.Rate, true
+		}
+	}
+
+	return offers[len(offers)-1].Rate, true
+}
@@ -0,0 +1,189 @@
+package poloniex
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+type MarginAccountSummary struct {
+	TotalValue         decimal.Decimal `json:"totalValue"`
+	PL                 decimal.Decimal `json:"pl"`
+	LendingFees        decimal.Decimal `json:"lendingFees"`
+	NetValue           decimal.Decimal `json:"netValue"`
+	TotalBorrowedValue decimal.Decimal `json:"totalBorrowedValue"`
+	CurrentMargin      decimal.Decimal `json:"currentMargin"`
+}
+
+// GetMarginAccountSummary returns summary of the margin account.
+func (p *Poloniex) GetMarginAccountSummary() (summary MarginAccountSummary, err error) {
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("returnMarginAccountSummary", nil, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &summary)
+	return
+}
+
+type MarginOrder struct {
+	OrderNumber     string         `json:"orderNumber"`
+	ClientOrderID   string         `json:"-"`
+	Message         string         `json:"message"`
+	ResultingTrades []ResultTrades `json:"resultingTrades"`
+}
+
+// MarginBuy places margin buy order. Zero lendingRate lets the server use the default maximum rate.
+// clientOrderID is optional.
+func (p *Poloniex) MarginBuy(market string, price, amount, lendingRate float64,
+	clientOrderID string) (marginOrder MarginOrder, err error) {
+
+	return p.marginOrder(SideBuy, market, price, amount, lendingRate, clientOrderID)
+}
+
+// MarginSell places margin sell order. Zero lendingRate lets the server use the default maximum rate.
+// clientOrderID is optional.
+func (p *Poloniex) MarginSell(market string, price, amount, lendingRate float64,
+	clientOrderID string) (marginOrder MarginOrder, err error) {
+
+	return p.marginOrder(SideSell, market, price, amount, lendingRate, clientOrderID)
+}
+
+func (p *Poloniex) marginOrder(side Side, market string, price, amount, lendingRate float64,
+	clientOrderID string) (marginOrder MarginOrder, err error) {
+
+	if p.markets != nil {
+		req := OrderRequest{Market: market, Side: side, Price: price, Amount: amount}
+		if err = p.markets.Check(&req); err != nil {
+			return
+		}
+		price, amount = req.Price, req.Amount
+	}
+
+	parameters := map[string]string{
+		"currencyPair": strings.ToUpper(market),
+		"rate":         strconv.FormatFloat(price, 'f', 8, 64),
+		"amount":       strconv.FormatFloat(amount, 'f', 8, 64),
+	}
+
+	if lendingRate > 0 {
+		parameters["lendingRate"] = strconv.FormatFloat(lendingRate, 'f', 8, 64)
+	}
+
+	if clientOrderID != "" {
+		if err = validateClientOrderID(clientOrderID); err != nil {
+			return
+		}
+		parameters["clientOrderId"] = clientOrderID
+	}
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	command := "marginBuy"
+	if side == SideSell {
+		command = "marginSell"
+	}
+
+	go p.tradingRequest(command, parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &marginOrder)
+	if err != nil {
+		return
+	}
+
+	marginOrder.ClientOrderID = clientOrderID
+
+	p.observe(side, parameters["currencyPair"], marginOrder.OrderNumber)
+
+	return
+}
+
+type MarginPosition struct {
+	Amount           decimal.Decimal `json:"amount"`
+	Total            decimal.Decimal `json:"total"`
+	BasePrice        decimal.Decimal `json:"basePrice"`
+	LiquidationPrice decimal.Decimal `json:"liquidationPrice"`
+	PL               decimal.Decimal `json:"pl"`
+	LendingFees      decimal.Decimal `json:"lendingFees"`
+	Type             string          `json:"type"` // "long", "short" or "none"
+}
+
+// GetMarginPosition returns margin position in the market.
+func (p *Poloniex) GetMarginPosition(market string) (position MarginPosition, err error) {
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	parameters := map[string]string{"currencyPair": strings.ToUpper(market)}
+	go p.tradingRequest("getMarginPosition", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &position)
+	return
+}
+
+// GetAllMarginPositions returns margin positions by market.
+func (p *Poloniex) GetAllMarginPositions() (positions map[string]MarginPosition, err error) {
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	parameters := map[string]string{"currencyPair": "all"}
+	go p.tradingRequest("getMarginPosition", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &positions)
+	return
+}
+
+type CloseMarginPosition struct {
+	Success         int                       `json:"success"`
+	Message         string                    `json:"message"`
+	ResultingTrades map[string][]ResultTrades `json:"resultingTrades"`
+}
+
+// CloseMarginPosition closes margin position in the market at market price.
+func (p *Poloniex) CloseMarginPosition(market string) (closePosition CloseMarginPosition, err error) {
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	parameters := map[string]string{"currencyPair": strings.ToUpper(market)}
+	go p.tradingRequest("closeMarginPosition", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &closePosition)
+	return
+}
@@ -0,0 +1,213 @@
+package poloniex
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// marketPrecision is a number of decimals of prices and amounts accepted by poloniex in every market.
+	marketPrecision = 8
+	// defaultMinOrderTotal is a minimum order total of markets not listed in minOrderTotals.
+	defaultMinOrderTotal = 0.0001
+	// defaultMarketInfoTTL is how long market info is used before it is refreshed.
+	defaultMarketInfoTTL = 5 * time.Minute
+)
+
+// minOrderTotals is a minimum order total by base currency of the market.
+var minOrderTotals = map[string]float64{
+	"BTC":  0.0001,
+	"ETH":  0.0001,
+	"TRX":  100,
+	"XMR":  0.0001,
+	"USDT": 1,
+	"USDC": 1,
+	"USDJ": 1,
+	"BUSD": 1,
+	"DAI":  1,
+	"PAX":  1,
+}
+
+// MarketInfo describes trading rules and state of a market.
+// Prices and amounts have marketPrecision decimals in every market.
+type MarketInfo struct {
+	Market    string
+	Base      string // currency the total is paid in, e.g. BTC in BTC_ETH
+	Quote     string
+	MinTotal  float64  // minimum price * amount
+	Frozen    bool     // trading is frozen
+	Suspended string   // reason why one of the currencies can not be traded, empty if both can
+	Disabled  []string // currencies with disabled deposits and withdrawals, trading is not affected
+}
+
+// MarketInfoCache keeps market info built from GetTickers and GetCurrencies.
+type MarketInfoCache struct {
+	client    *Poloniex
+	TTL       time.Duration // market info older than TTL is refreshed on access
+	Round     bool          // round price and amount to market precision instead of rejecting the order
+	markets   map[string]MarketInfo
+	updatedAt time.Time
+	mu        sync.Mutex
+}
+
+// NewMarketInfoCache creates cache filled on first access.
+func NewMarketInfoCache(client *Poloniex) *MarketInfoCache {
+	return &MarketInfoCache{
+		client: client,
+		TTL:    defaultMarketInfoTTL,
+	}
+}
+
+// SetMarketInfo enables validation of orders with market info before they are sent.
+// Nil cache disables it.
+func (p *Poloniex) SetMarketInfo(cache *MarketInfoCache) {
+	p.markets = cache
+}
+
+// Refresh reloads market info.
+func (c *MarketInfoCache) Refresh() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.refresh()
+}
+
+// Market returns info of the market.
+// If refresh of outdated info fails, the outdated info is returned.
+func (c *MarketInfoCache) Market(market string) (info MarketInfo, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.markets == nil || time.Since(c.updatedAt) > c.TTL {
+		if err = c.refresh(); err != nil {
+			if c.markets == nil {
+				return
+			}
+			logger.WithError(err).Warn("can not refresh market info, outdated info is used")
+		}
+	}
+
+	info, ok := c.markets[strings.ToUpper(market)]
+	if !ok {
+		return info, Error(MarketRuleError, "unknown market "+market)
+	}
+
+	return info, nil
+}
+
+// Check validates the order with market rules. If Round is set, price and amount
+// are rounded to market precision: amount down, buy price down and sell price up.
+func (c *MarketInfoCache) Check(req *OrderRequest) error {
+	info, err := c.Market(req.Market)
+	if err != nil {
+		return err
+	}
+
+	if info.Frozen {
+		return Error(MarketRuleError, info.Market+" is frozen")
+	}
+
+	if info.Suspended != "" {
+		return Error(MarketRuleError, info.Suspended)
+	}
+
+	price, priceExact := roundDecimals(req.Price, marketPrecision, req.Side == SideSell)
+	amount, amountExact := roundDecimals(req.Amount, marketPrecision, false)
+
+	if !c.Round {
+		if !priceExact {
+			return Error(MarketRuleError, fmt.Sprintf("price %v has more than %d decimals", req.Price, marketPrecision))
+		}
+
+		if !amountExact {
+			return Error(MarketRuleError, fmt.Sprintf("amount %v has more than %d decimals", req.Amount, marketPrecision))
+		}
+	}
+
+	if amount <= 0 {
+		return Error(MarketRuleError, fmt.Sprintf("amount %v is rounded to zero", req.Amount))
+	}
+
+	if total := price * amount; total < info.MinTotal {
+		return Error(MarketRuleError, fmt.Sprintf("total %v is below minimum %v %s", total, info.MinTotal, info.Base))
+	}
+
+	req.Price, req.Amount = price, amount
+	return nil
+}
+
+func (c *MarketInfoCache) refresh() error {
+	tickers, err := c.client.GetTickers()
+	if err != nil {
+		return err
+	}
+
+	currencies, err := c.client.GetCurrencies()
+	if err != nil {
+		return err
+	}
+
+	markets := make(map[string]MarketInfo, len(tickers))
+	for market, ticker := range tickers {
+		base, quote := splitMarket(market)
+
+		minTotal, ok := minOrderTotals[base]
+		if !ok {
+			minTotal = defaultMinOrderTotal
+		}
+
+		info := MarketInfo{
+			Market:   market,
+			Base:     base,
+			Quote:    quote,
+			MinTotal: minTotal,
+			Frozen:   ticker.IsFrozen == 1,
+		}
+
+		for _, currency := range []string{base, quote} {
+			status, ok := currencies[currency]
+			if !ok {
+				continue
+			}
+
+			switch {
+			case status.Delisted == 1:
+				info.Suspended = currency + " is delisted"
+			case status.Frozen == 1:
+				info.Suspended = currency + " is frozen"
+			}
+
+			if status.Disabled == 1 {
+				info.Disabled = append(info.Disabled, currency)
+			}
+		}
+
+		markets[market] = info
+	}
+
+	c.markets = markets
+	c.updatedAt = time.Now()
+
+	return nil
+}
+
+// Returns v rounded to precision decimals and whether it was already rounded.
+func roundDecimals(v float64, precision int, up bool) (float64, bool) {
+	d := decimal.NewFromFloat(v)
+	rounded := d.Truncate(int32(precision))
+
+	if rounded.Equal(d) {
+		return v, true
+	}
+
+	if up {
+		rounded = rounded.Add(decimal.New(1, -int32(precision)))
+	}
+
+	f, _ := rounded.Float64()
+	return f, false
+}
@@ -3,38 +3,192 @@ package poloniex
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
+// defaultRetention is how long finished orders stay observable for late trades.
+const defaultRetention = time.Minute
+
+// OrderObserver keeps orders placed through REST API,
+// so trades from websocket can be matched with side and market of the order.
 type OrderObserver interface {
-	Observe(side, symbol, orderID string) error
-	Items(orderID string) (ServerableObject, error)
-	// TODO: Delete after order completely fill.
+	// Observe starts observing the order.
+	Observe(side Side, symbol, orderID string) error
+	// Items returns observed order.
+	Items(orderID string) (ObservedOrder, error)
+	// Delete stops observing the order.
 	Delete(orderID string) error
+	// Move observes the order placed by moveOrder instead of the original one.
+	Move(orderID, newOrderID string) error
 	Lock() error
 	Unlock()
 	IsObservable(orderID string) bool
 }
 
-type ServerableObject struct {
-	side    string
-	symbol  string
-	orderID string
+// ObservedOrder is an order kept by OrderObserver.
+type ObservedOrder struct {
+	Side    Side   `json:"side"`
+	Symbol  string `json:"symbol"`
+	OrderID string `json:"orderId"`
 }
 
+// ServerableObject is the former name of ObservedOrder.
+//
+// Deprecated: use ObservedOrder.
+type ServerableObject = ObservedOrder
+
+// Built-in observers.
+var (
+	_ OrderObserver = (*WebsocketObserver)(nil)
+	_ OrderObserver = (*FileObserver)(nil)
+	_ OrderObserver = (*NilObserver)(nil)
+)
+
 // WebsocketObserver реализация OrderObserver для Websocket.
 // Используется для синхронизации WS & REST
 type WebsocketObserver struct {
-	items   map[string]ServerableObject
-	itemsMu sync.RWMutex
-	mu      sync.Mutex
+	items     map[string]ObservedOrder
+	finished  finishedOrders // completed orders waiting for removal, observed or not yet
+	retention time.Duration
+	cleanup   *time.Timer  // removes finished orders when their retention is over
+	removed   uint64       // orders removed automatically
+	onChange  func() error // called after items are changed
+	itemsMu   sync.RWMutex
+	mu        sync.Mutex
+}
+
+// ObserverStats is a snapshot of observer counters.
+type ObserverStats struct {
+	Tracked  int    // observed orders, including finished ones
+	Finished int    // finished orders waiting for removal
+	Removed  uint64 // orders removed automatically since start
 }
 
 func NewWebsocketObserver() *WebsocketObserver {
 	return &WebsocketObserver{
-		items: make(map[string]ServerableObject),
+		items:     make(map[string]ObservedOrder),
+		finished:  make(finishedOrders),
+		retention: defaultRetention,
+	}
+}
+
+// SetRetention sets how long completed or canceled orders are kept
+// before removal, so trades arriving after the order update are still reported.
+func (w *WebsocketObserver) SetRetention(retention time.Duration) {
+	w.itemsMu.Lock()
+	w.retention = retention
+	w.itemsMu.Unlock()
+}
+
+// Stats returns observer counters.
+func (w *WebsocketObserver) Stats() ObserverStats {
+	w.itemsMu.RLock()
+	defer w.itemsMu.RUnlock()
+
+	return ObserverStats{
+		Tracked:  len(w.items),
+		Finished: len(w.finished),
+		Removed:  w.removed,
 	}
 }
 
+// Apply marks orders completed by "o" updates with zero amount, cancels and kills as finished.
+// Orders are marked even if they are not observed yet, because the update may arrive
+// before the REST response; such orders are removed when retention is over as well.
+// Finished orders are removed by a timer after retention.
+// NewPrivateWSClient registers it as account notification handler.
+func (w *WebsocketObserver) Apply(updates []AccountUpdate) {
+	now := time.Now()
+
+	w.itemsMu.Lock()
+	observed := false
+	for _, update := range updates {
+		if orderID, ok := finishedOrder(update.Data); ok {
+			w.finished.add(orderID, now)
+			_, ok = w.items[orderID]
+			observed = observed || ok
+		}
+	}
+	w.scheduleCleanup(now)
+	w.itemsMu.Unlock()
+
+	// finish times are persisted, so finished orders expire after restart as well
+	if observed {
+		if err := w.changed(); err != nil {
+			logger.WithError(err).Error("can not save observer")
+		}
+	}
+}
+
+// Finish marks the order as finished, so it is removed when retention is over.
+func (w *WebsocketObserver) Finish(orderID string) {
+	now := time.Now()
+
+	w.itemsMu.Lock()
+	w.finished.add(orderID, now)
+	w.scheduleCleanup(now)
+	_, observed := w.items[orderID]
+	w.itemsMu.Unlock()
+
+	if observed {
+		if err := w.changed(); err != nil {
+			logger.WithError(err).Error("can not save observer")
+		}
+	}
+}
+
+// Cleanup removes orders finished earlier than retention before now.
+func (w *WebsocketObserver) Cleanup(now time.Time) (removed int, err error) {
+	w.itemsMu.Lock()
+	for _, orderID := range w.finished.expire(now.Add(-w.retention)) {
+		if _, ok := w.items[orderID]; ok {
+			delete(w.items, orderID)
+			removed++
+		}
+	}
+	w.removed += uint64(removed)
+	w.scheduleCleanup(now)
+	w.itemsMu.Unlock()
+
+	if removed == 0 {
+		return 0, nil
+	}
+
+	return removed, w.changed()
+}
+
+// Start timer which removes the earliest finished order when its retention is over.
+// It must be called with itemsMu locked.
+func (w *WebsocketObserver) scheduleCleanup(now time.Time) {
+	if w.cleanup != nil {
+		return
+	}
+
+	earliest, ok := w.finished.earliest()
+	if !ok {
+		return
+	}
+
+	w.cleanup = time.AfterFunc(earliest.Add(w.retention).Sub(now), func() {
+		w.itemsMu.Lock()
+		w.cleanup = nil
+		w.itemsMu.Unlock()
+
+		if _, err := w.Cleanup(time.Now()); err != nil {
+			logger.WithError(err).Error("can not save observer")
+		}
+	})
+}
+
+// Call change hook if set.
+func (w *WebsocketObserver) changed() error {
+	if w.onChange == nil {
+		return nil
+	}
+
+	return w.onChange()
+}
+
 func (w *WebsocketObserver) IsObservable(orderID string) bool {
 	w.itemsMu.RLock()
 	defer w.itemsMu.RUnlock()
@@ -46,7 +200,9 @@ func (w *WebsocketObserver) IsObservable(orderID string) bool {
 	return false
 }
 
-func (w *WebsocketObserver) Observe(side, symbol, orderID string) error {
+// Observe registers the order. Order which is already finished is still removed
+// when its retention is over.
+func (w *WebsocketObserver) Observe(side Side, symbol, orderID string) error {
 	w.itemsMu.RLock()
 
 	if _, ok := w.items[orderID]; ok {
@@ -56,17 +212,17 @@ func (w *WebsocketObserver) Observe(side, symbol, orderID string) error {
 
 	w.itemsMu.RUnlock()
 	w.itemsMu.Lock()
-	w.items[orderID] = ServerableObject{
-		side:    side,
-		symbol:  symbol,
-		orderID: orderID,
+	w.items[orderID] = ObservedOrder{
+		Side:    side,
+		Symbol:  symbol,
+		OrderID: orderID,
 	}
 	w.itemsMu.Unlock()
 
-	return nil
+	return w.changed()
 }
 
-func (w *WebsocketObserver) Items(orderID string) (ServerableObject, error) {
+func (w *WebsocketObserver) Items(orderID string) (ObservedOrder, error) {
 	w.itemsMu.RLock()
 
 	if value, ok := w.items[orderID]; ok {
@@ -75,7 +231,7 @@ func (w *WebsocketObserver) Items(orderID string) (ServerableObject, error) {
 	}
 
 	w.itemsMu.RUnlock()
-	return ServerableObject{}, fmt.Errorf("orderID %v not registered", orderID)
+	return ObservedOrder{}, fmt.Errorf("orderID %v not registered", orderID)
 }
 
 func (w *WebsocketObserver) Delete(orderID string) error {
@@ -88,9 +244,37 @@ func (w *WebsocketObserver) Delete(orderID string) error {
 
 	w.itemsMu.Lock()
 	delete(w.items, orderID)
+	delete(w.finished, orderID)
 	w.itemsMu.Unlock()
 
-	return nil
+	return w.changed()
+}
+
+// Move observes the order placed by moveOrder and finishes the original one.
+func (w *WebsocketObserver) Move(orderID, newOrderID string) error {
+	w.itemsMu.Lock()
+
+	item, ok := w.items[orderID]
+	if !ok {
+		w.itemsMu.Unlock()
+		return fmt.Errorf("not found: %v", orderID)
+	}
+
+	if _, ok := w.items[newOrderID]; ok {
+		w.itemsMu.Unlock()
+		return fmt.Errorf("already exists: %v", newOrderID)
+	}
+
+	// the original order is kept until retention is over, so its late trades are reported
+	now := time.Now()
+	w.finished.add(orderID, now)
+	w.scheduleCleanup(now)
+
+	item.OrderID = newOrderID
+	w.items[newOrderID] = item
+	w.itemsMu.Unlock()
+
+	return w.changed()
 }
 
 // Lock TODO: Сделать кастомный Locker, чтобы возвращать ошибку, что блокировка длится дольше T
@@ -110,11 +294,19 @@ func NewNilObserver() *NilObserver {
 	return &NilObserver{}
 }
 
-func (n *NilObserver) Observe(_, _ string, _ int64) error {
+func (n *NilObserver) Observe(_ Side, _, _ string) error {
+	return nil
+}
+
+func (n *NilObserver) Items(orderID string) (ObservedOrder, error) {
+	return ObservedOrder{}, fmt.Errorf("orderID %v not registered", orderID)
+}
+
+func (n *NilObserver) Delete(_ string) error {
 	return nil
 }
 
-func (n *NilObserver) Delete(_ int64) error {
+func (n *NilObserver) Move(_, _ string) error {
 	return nil
 }
 
@@ -125,6 +317,6 @@ func (n *NilObserver) Lock() error {
 func (n *NilObserver) Unlock() {
 }
 
-func (n *NilObserver) IsObservable(orderID int64) bool {
+func (n *NilObserver) IsObservable(_ string) bool {
 	return false
 }
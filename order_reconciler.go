@@ -0,0 +1,295 @@
+package poloniex
+
+import (
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// defaultReconcileGrace is how long recently updated orders are not reconciled.
+const defaultReconcileGrace = 10 * time.Second
+
+// DiscrepancyKind is a kind of difference between tracked and server order state.
+type DiscrepancyKind int
+
+// List of discrepancy kinds.
+const (
+	DiscrepancyOrphaned       DiscrepancyKind = iota + 1 // open on the server, not open in tracker
+	DiscrepancyMissing                                   // open in tracker, not found on the server
+	DiscrepancyAmountMismatch                            // remaining amounts differ
+)
+
+var discrepancyKindNames = []string{"unknown", "orphaned", "missing", "amount mismatch"}
+
+func (k DiscrepancyKind) String() string {
+	return enumString(discrepancyKindNames, int(k))
+}
+
+// Discrepancy is a difference between tracked order and the order on the server.
+type Discrepancy struct {
+	Kind        DiscrepancyKind
+	OrderNumber string
+	Market      string
+	Local       OrderState // zero for orphaned orders unknown to tracker
+	Remote      OpenOrder  // zero for missing orders
+	Repaired    bool
+}
+
+// OrderReconciler compares orders of OrderTracker with open orders returned by REST API.
+// It is useful after websocket reconnects, when account notifications may be lost.
+type OrderReconciler struct {
+	dropped       uint64 // accessed atomically, first field for 64-bit alignment
+	client        *Poloniex
+	tracker       *OrderTracker
+	Repair        bool          // update tracker with the server state
+	Grace         time.Duration // orders updated more recently are skipped
+	discrepancies chan Discrepancy
+}
+
+// NewOrderReconciler creates reconciler of tracker with client orders.
+func NewOrderReconciler(client *Poloniex, tracker *OrderTracker) *OrderReconciler {
+	return &OrderReconciler{
+		client:        client,
+		tracker:       tracker,
+		Grace:         defaultReconcileGrace,
+		discrepancies: make(chan Discrepancy, SUBSBUFFER),
+	}
+}
+
+// Discrepancies returns the stream of discrepancies found by Run.
+// Repair does not depend on the stream, discrepancies which do not fit are counted by Dropped.
+func (r *OrderReconciler) Discrepancies() <-chan Discrepancy {
+	return r.discrepancies
+}
+
+// Dropped returns the number of discrepancies not sent to the full Discrepancies channel.
+func (r *OrderReconciler) Dropped() uint64 {
+	return atomic.LoadUint64(&r.dropped)
+}
+
+// Run reconciles orders every interval until stop is closed.
+// Errors are logged, discrepancies are sent to Discrepancies channel even if there was an error.
+func (r *OrderReconciler) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		discrepancies, err := r.Reconcile()
+		if err != nil {
+			logger.WithError(err).Error("can not reconcile orders")
+		}
+
+		for _, d := range discrepancies {
+			logger.WithField("orderNumber", d.OrderNumber).Warnf("order discrepancy: %v", d.Kind)
+
+			select {
+			case r.discrepancies <- d:
+			default:
+				atomic.AddUint64(&r.dropped, 1)
+			}
+		}
+	}
+}
+
+// Reconcile compares tracked orders with open orders on the server once.
+// Tracked orders absent from open orders are checked with GetOrderStat before they are reported missing.
+// Failed checks of single orders do not stop reconciliation, the first error is returned
+// together with discrepancies found and repaired.
+func (r *OrderReconciler) Reconcile() (discrepancies []Discrepancy, err error) {
+	openOrders, err := r.client.GetAllOpenOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	type remoteOrder struct {
+		market string
+		order  OpenOrder
+	}
+
+	remote := make(map[string]remoteOrder)
+	for market, orders := range openOrders {
+		for _, order := range orders {
+			remote[order.OrderNumber] = remoteOrder{market: market, order: order}
+		}
+	}
+
+	deadline := time.Now().Add(-r.Grace)
+	var orderErr error
+
+	local := make(map[string]OrderState)
+	for _, order := range r.tracker.Orders() {
+		local[order.OrderNumber] = order
+	}
+
+	for orderNumber, rem := range remote {
+		order, ok := local[orderNumber]
+		if ok && order.UpdatedAt.After(deadline) {
+			continue
+		}
+
+		d := Discrepancy{
+			OrderNumber: orderNumber,
+			Market:      rem.market,
+			Local:       order,
+			Remote:      rem.order,
+		}
+
+		remaining, _ := rem.order.Amount.Float64()
+
+		switch {
+		case !ok || order.Status.IsFinal():
+			d.Kind = DiscrepancyOrphaned
+		case math.Abs(order.Remaining-remaining) > amountEpsilon:
+			d.Kind = DiscrepancyAmountMismatch
+		default:
+			continue
+		}
+
+		if r.Repair {
+			r.tracker.restoreOpen(rem.market, rem.order)
+			d.Repaired = true
+		}
+
+		discrepancies = append(discrepancies, d)
+	}
+
+	for orderNumber, order := range local {
+		if _, ok := remote[orderNumber]; ok || order.Status.IsFinal() || order.UpdatedAt.After(deadline) {
+			continue
+		}
+
+		// order may have been placed after the open orders request
+		_, err := r.client.GetOrderStat(orderNumber)
+		if err == nil {
+			continue
+		}
+		if !isNotFound(err) {
+			if orderErr == nil {
+				orderErr = err
+			}
+			continue
+		}
+
+		d := Discrepancy{
+			Kind:        DiscrepancyMissing,
+			OrderNumber: orderNumber,
+			Market:      order.Market,
+			Local:       order,
+		}
+
+		if r.Repair {
+			// order without trades is not found as well
+			trades, err := r.client.GetTradesByOrderID(orderNumber)
+			if err == nil || isNotFound(err) {
+				r.tracker.closeMissing(orderNumber, trades)
+				d.Repaired = true
+			} else if orderErr == nil {
+				orderErr = err
+			}
+		}
+
+		discrepancies = append(discrepancies, d)
+	}
+
+	return discrepancies, orderErr
+}
+
+// Check whether the server error means the order is not found.
+func isNotFound(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "not found")
+}
+
+// Replace state of the order with the open order returned by the server.
+func (t *OrderTracker) restoreOpen(market string, open OpenOrder) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order := t.order(open.OrderNumber, "")
+	order.Market = market
+	if side, err := ParseSide(open.Type); err == nil {
+		order.Side = side
+	}
+	order.Rate, _ = open.Price.Float64()
+	order.OriginalAmount, _ = open.StartingAmount.Float64()
+	order.Remaining, _ = open.Amount.Float64()
+
+	status := OrderStatusOpen
+	if order.Remaining < order.OriginalAmount-amountEpsilon {
+		status = OrderStatusPartiallyFilled
+	}
+
+	t.forceStatus(order, status)
+}
+
+// Close the order which is not found on the server.
+// Executed amount, average price and fee are rebuilt from trades of the order,
+// then fully executed order becomes filled, otherwise it is considered canceled.
+func (t *OrderTracker) closeMissing(orderNumber string, trades []OrderTrade) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.orders[orderNumber]
+	if !ok {
+		return
+	}
+
+	order.Executed, order.executedTotal, order.AveragePrice, order.TotalFee = 0, 0, 0, 0
+	for _, trade := range trades {
+		rate, _ := trade.Price.Float64()
+		amount, _ := trade.Amount.Float64()
+		total, _ := trade.Total.Float64()
+		feeRate, _ := trade.Fee.Float64()
+
+		order.Executed += amount
+		order.executedTotal += rate * amount
+
+		// fee is taken from the bought currency
+		if side, err := ParseSide(trade.Type); err == nil && side == SideBuy {
+			order.TotalFee += amount * feeRate
+		} else {
+			order.TotalFee += total * feeRate
+		}
+	}
+	if order.Executed > 0 {
+		order.AveragePrice = order.executedTotal / order.Executed
+	}
+
+	status := OrderStatusCanceled
+	if order.OriginalAmount > 0 && order.Executed >= order.OriginalAmount-amountEpsilon {
+		status = OrderStatusFilled
+	} else if order.OriginalAmount > order.Executed {
+		order.Canceled = order.OriginalAmount - order.Executed
+	}
+
+	order.Remaining = 0
+	t.forceStatus(order, status)
+}
+
+// Change order status even if it is final and publish transition with zero cause.
+func (t *OrderTracker) forceStatus(order *OrderState, status OrderStatus) {
+	order.UpdatedAt = time.Now()
+
+	if order.Status == status {
+		return
+	}
+
+	transition := OrderTransition{
+		From: order.Status,
+		To:   status,
+	}
+	order.Status = status
+	transition.Order = *order
+
+	select {
+	case t.transitions <- transition:
+	default:
+		t.dropped++
+	}
+}
@@ -0,0 +1,363 @@
+package poloniex
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Server errors returned for postOnly and fillOrKill orders which can not be executed.
+const (
+	postOnlyRejected   = "Unable to place post-only order at this price"
+	fillOrKillRejected = "Unable to fill order completely"
+)
+
+// OrderOutcome shows what happened to the order right after placement.
+type OrderOutcome string
+
+// List of order outcomes.
+const (
+	OrderOutcomeRested            OrderOutcome = "rested"             // nothing executed, order is on the book
+	OrderOutcomePartiallyExecuted OrderOutcome = "partially executed" // part of amount executed
+	OrderOutcomeExecuted          OrderOutcome = "executed"           // whole amount executed
+	OrderOutcomeKilled            OrderOutcome = "killed"             // nothing executed, order is not on the book
+)
+
+// OrderRequest describes a limit order.
+// PostOnly, FillOrKill and ImmediateOrCancel are mutually exclusive.
+type OrderRequest struct {
+	Market            string
+	Side              Side
+	Price             float64
+	Amount            float64
+	ClientOrderID     string
+	PostOnly          bool // order must rest on the book, otherwise it is killed
+	FillOrKill        bool // order must be executed completely, otherwise it is killed
+	ImmediateOrCancel bool // unfilled part of the order is canceled
+}
+
+// Validate checks the request before it is sent.
+func (r *OrderRequest) Validate() error {
+	if r.Market == "" {
+		return Error(OrderRequestError, "market is empty")
+	}
+
+	if !r.Side.isValid() {
+		return Error(OrderRequestError, "unknown side "+r.Side.String())
+	}
+
+	if r.Price <= 0 {
+		return Error(OrderRequestError, "price must be positive")
+	}
+
+	if r.Amount <= 0 {
+		return Error(OrderRequestError, "amount must be positive")
+	}
+
+	flags := 0
+	for _, flag := range []bool{r.PostOnly, r.FillOrKill, r.ImmediateOrCancel} {
+		if flag {
+			flags++
+		}
+	}
+	if flags > 1 {
+		return Error(OrderRequestError, "postOnly, fillOrKill and immediateOrCancel are mutually exclusive")
+	}
+
+	if r.ClientOrderID != "" {
+		return validateClientOrderID(r.ClientOrderID)
+	}
+
+	return nil
+}
+
+// Returns trading api parameters for the request.
+func (r *OrderRequest) parameters() map[string]string {
+	parameters := map[string]string{
+		"currencyPair": strings.ToUpper(r.Market),
+		"rate":         strconv.FormatFloat(r.Price, 'f', 8, 64),
+		"amount":       strconv.FormatFloat(r.Amount, 'f', 8, 64),
+	}
+
+	if r.ClientOrderID != "" {
+		parameters["clientOrderId"] = r.ClientOrderID
+	}
+
+	switch {
+	case r.PostOnly:
+		parameters["postOnly"] = "1"
+	case r.FillOrKill:
+		parameters["fillOrKill"] = "1"
+	case r.ImmediateOrCancel:
+		parameters["immediateOrCancel"] = "1"
+	}
+
+	return parameters
+}
+
+// OrderResult is a parsed response on order placement.
+type OrderResult struct {
+	Buy
+	AmountUnfilled decimal.Decimal `json:"amountUnfilled"`
+	Executed       decimal.Decimal `json:"-"`
+	Outcome        OrderOutcome    `json:"-"`
+}
+
+// PlaceOrder validates and sends the order and registers it in observer.
+// If market info is set with SetMarketInfo, the order is checked with market rules as well.
+// A postOnly or fillOrKill order rejected by the server is not an error,
+// it is reported with OrderOutcomeKilled.
+func (p *Poloniex) PlaceOrder(req OrderRequest) (result OrderResult, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	if p.markets != nil {
+		if err = p.markets.Check(&req); err != nil {
+			return
+		}
+	}
+
+	parameters := req.parameters()
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest(req.Side.String(), parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		if (req.PostOnly && strings.Contains(err.Error(), postOnlyRejected)) ||
+			(req.FillOrKill && strings.Contains(err.Error(), fillOrKillRejected)) {
+			result.ClientOrderID = req.ClientOrderID
+			result.AmountUnfilled = decimal.NewFromFloat(req.Amount)
+			result.Outcome = OrderOutcomeKilled
+			return result, nil
+		}
+		return
+	}
+
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return
+	}
+
+	result.ClientOrderID = req.ClientOrderID
+	result.setOutcome(&req)
+
+	p.observe(req.Side, parameters["currencyPair"], result.OrderNumber)
+
+	return
+}
+
+// Calculate executed amount and outcome from resulting trades.
+func (r *OrderResult) setOutcome(req *OrderRequest) {
+	amount := decimal.NewFromFloat(req.Amount)
+
+	r.Executed = decimal.Zero
+	for _, trade := range r.ResultingTrades {
+		r.Executed = r.Executed.Add(trade.Amount)
+	}
+
+	if r.AmountUnfilled.IsZero() {
+		r.AmountUnfilled = amount.Sub(r.Executed)
+		if r.AmountUnfilled.IsNegative() {
+			r.AmountUnfilled = decimal.Zero
+		}
+	}
+
+	switch {
+	case r.AmountUnfilled.IsZero():
+		r.Outcome = OrderOutcomeExecuted
+	case r.Executed.IsZero() && (req.ImmediateOrCancel || req.FillOrKill):
+		r.Outcome = OrderOutcomeKilled
+	case r.Executed.IsZero():
+		r.Outcome = OrderOutcomeRested
+	default:
+		r.Outcome = OrderOutcomePartiallyExecuted
+	}
+}
+
+// MoveOrderRequest describes cancel-replace of an open order.
+// PostOnly and ImmediateOrCancel are mutually exclusive.
+type MoveOrderRequest struct {
+	OrderNumber       string
+	Market            string // optional, observes the new order if the original one is not observed
+	Side              Side   // optional, as Market
+	Price             float64
+	Amount            float64 // zero keeps amount of the original order
+	ClientOrderID     string
+	PostOnly          bool
+	ImmediateOrCancel bool
+}
+
+// Validate checks the request before it is sent.
+func (r *MoveOrderRequest) Validate() error {
+	if r.OrderNumber == "" {
+		return Error(OrderRequestError, "order number is empty")
+	}
+
+	if r.Price <= 0 {
+		return Error(OrderRequestError, "price must be positive")
+	}
+
+	if r.Amount < 0 {
+		return Error(OrderRequestError, "amount must not be negative")
+	}
+
+	if r.PostOnly && r.ImmediateOrCancel {
+		return Error(OrderRequestError, "postOnly and immediateOrCancel are mutually exclusive")
+	}
+
+	if r.ClientOrderID != "" {
+		return validateClientOrderID(r.ClientOrderID)
+	}
+
+	return nil
+}
+
+// MoveOrder is a response on moveOrder, resulting trades are grouped by market.
+type MoveOrder struct {
+	Success         int                       `json:"success"`
+	OrderNumber     string                    `json:"orderNumber"`
+	ClientOrderID   string                    `json:"-"`
+	ResultingTrades map[string][]ResultTrades `json:"resultingTrades"`
+}
+
+// MoveOrder atomically cancels the order and places a new one with new price and amount.
+// Observer keeps tracking the order under the new order number, the original order
+// is kept until retention is over. New order is observed even if the original one was not.
+// If market info is set with SetMarketInfo, the new order is checked with market rules as well.
+func (p *Poloniex) MoveOrder(req MoveOrderRequest) (moveOrder MoveOrder, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	if p.markets != nil {
+		if err = p.checkMove(&req); err != nil {
+			return
+		}
+	}
+
+	parameters := map[string]string{
+		"orderNumber": req.OrderNumber,
+		"rate":        strconv.FormatFloat(req.Price, 'f', 8, 64),
+	}
+
+	if req.Amount > 0 {
+		parameters["amount"] = strconv.FormatFloat(req.Amount, 'f', 8, 64)
+	}
+
+	if req.ClientOrderID != "" {
+		parameters["clientOrderId"] = req.ClientOrderID
+	}
+
+	switch {
+	case req.PostOnly:
+		parameters["postOnly"] = "1"
+	case req.ImmediateOrCancel:
+		parameters["immediateOrCancel"] = "1"
+	}
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("moveOrder", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &moveOrder)
+	if err != nil {
+		return
+	}
+
+	if moveOrder.Success != 1 {
+		return moveOrder, Error(ServerError, string(resp))
+	}
+
+	moveOrder.ClientOrderID = req.ClientOrderID
+
+	if p.observer.Lock() != nil {
+		return
+	}
+	moveErr := p.observer.Move(req.OrderNumber, moveOrder.OrderNumber)
+	p.observer.Unlock()
+
+	if moveErr != nil {
+		p.observeMoved(req, moveOrder.OrderNumber)
+	}
+
+	return
+}
+
+// Check the new order of moveOrder with market rules. Market, side and amount
+// which are not set in the request are taken from the order status.
+func (p *Poloniex) checkMove(req *MoveOrderRequest) error {
+	order := OrderRequest{
+		Market: req.Market,
+		Side:   req.Side,
+		Price:  req.Price,
+		Amount: req.Amount,
+	}
+
+	if order.Market == "" || !order.Side.isValid() || order.Amount == 0 {
+		stat, err := p.GetOrderStat(req.OrderNumber)
+		if err != nil {
+			return err
+		}
+
+		if order.Market == "" {
+			order.Market = stat.CurrencyPair
+		}
+		if !order.Side.isValid() {
+			if order.Side, err = ParseSide(stat.Type); err != nil {
+				return err
+			}
+		}
+		if order.Amount == 0 {
+			order.Amount, _ = stat.Amount.Float64()
+		}
+	}
+
+	if err := p.markets.Check(&order); err != nil {
+		return err
+	}
+
+	req.Market, req.Side, req.Price = order.Market, order.Side, order.Price
+	if req.Amount > 0 {
+		req.Amount = order.Amount
+	}
+
+	return nil
+}
+
+// Observe the order placed by moveOrder when the original order was not observed.
+// Market and side are taken from the request or from the order status.
+func (p *Poloniex) observeMoved(req MoveOrderRequest, orderNumber string) {
+	market, side := strings.ToUpper(req.Market), req.Side
+
+	if market == "" || !side.isValid() {
+		stat, err := p.GetOrderStat(orderNumber)
+		if err != nil {
+			logger.WithError(err).WithField("orderNumber", orderNumber).Error("can not observe moved order")
+			return
+		}
+
+		market = stat.CurrencyPair
+		if side, err = ParseSide(stat.Type); err != nil {
+			logger.WithError(err).WithField("orderNumber", orderNumber).Error("can not observe moved order")
+			return
+		}
+	}
+
+	p.observe(side, market, orderNumber)
+}
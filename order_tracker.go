@@ -0,0 +1,339 @@
+package poloniex
+
+import (
+	"sync"
+	"time"
+)
+
+// amounts below amountEpsilon are treated as zero.
+const amountEpsilon = 1e-9
+
+// finishedOrder returns order number if the account notification means that no more updates
+// are expected for the order: it is filled, canceled or killed.
+// OrderTracker, WebsocketObserver and ListeningReports share it to agree on finished orders.
+func finishedOrder(data interface{}) (orderNumber string, ok bool) {
+	switch data := data.(type) {
+	case OrderUpdate:
+		return data.OrderNumber, data.OrderType == OrderUpdateCanceled || data.NewAmount < amountEpsilon
+	case Kill:
+		return data.OrderNumber, true
+	default:
+		return "", false
+	}
+}
+
+// finishedOrders keeps finish time of orders, so they are forgotten after a retention window.
+type finishedOrders map[string]time.Time
+
+// Remember the order as finished at now, unless it is already finished.
+func (f finishedOrders) add(orderNumber string, now time.Time) {
+	if _, ok := f[orderNumber]; !ok {
+		f[orderNumber] = now
+	}
+}
+
+// Return the earliest finish time.
+func (f finishedOrders) earliest() (earliest time.Time, ok bool) {
+	for _, finishedAt := range f {
+		if !ok || finishedAt.Before(earliest) {
+			earliest, ok = finishedAt, true
+		}
+	}
+
+	return earliest, ok
+}
+
+// Remove and return orders finished before deadline.
+func (f finishedOrders) expire(deadline time.Time) (expired []string) {
+	for orderNumber, finishedAt := range f {
+		if finishedAt.Before(deadline) {
+			expired = append(expired, orderNumber)
+			delete(f, orderNumber)
+		}
+	}
+
+	return expired
+}
+
+// OrderState is a snapshot of a single order tracked by OrderTracker.
+type OrderState struct {
+	OrderNumber    string
+	ClientOrderID  string
+	CurrencyPairID string
+	Market         string
+	Side           Side
+	Status         OrderStatus
+	Rate           float64
+	OriginalAmount float64
+	Remaining      float64
+	Executed       float64
+	Canceled       float64
+	AveragePrice   float64
+	TotalFee       float64
+	MarginAmount   float64
+	UpdatedAt      time.Time
+
+	executedTotal float64
+}
+
+// OrderTransition describes a change of order status.
+type OrderTransition struct {
+	From  OrderStatus
+	To    OrderStatus
+	Cause UpdateType // account notification type which caused the transition, zero if repaired by OrderReconciler
+	Order OrderState
+}
+
+// OrderTracker is an order state machine fed by account notifications.
+// Orders are keyed by order number and can be looked up by clientOrderId.
+type OrderTracker struct {
+	orders      map[string]*OrderState
+	byClientID  map[string]string
+	transitions chan OrderTransition
+	dropped     uint64 // transitions not sent because the channel was full
+	mu          sync.RWMutex
+}
+
+// NewOrderTracker creates an empty order tracker.
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{
+		orders:      make(map[string]*OrderState),
+		byClientID:  make(map[string]string),
+		transitions: make(chan OrderTransition, SUBSBUFFER),
+	}
+}
+
+// TrackOrders subscribes to account notification and feeds them into tracker.
+// The returned function stops tracking.
+func (ws *WSClient) TrackOrders(tracker *OrderTracker) (cancel func(), err error) {
+	if _, ok := ws.Subs["ACCOUNT"]; !ok {
+		if err = ws.SubscribeAccount(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ws.OnAccountUpdate(tracker.Apply), nil
+}
+
+// Transitions returns the stream of order status changes.
+// Order state is kept up to date when the stream is full, only the transition is lost; see Dropped.
+func (t *OrderTracker) Transitions() <-chan OrderTransition {
+	return t.transitions
+}
+
+// Dropped returns the number of transitions not sent to the full Transitions channel.
+func (t *OrderTracker) Dropped() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.dropped
+}
+
+// Order returns state of the order by order number.
+func (t *OrderTracker) Order(orderNumber string) (OrderState, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	order, ok := t.orders[orderNumber]
+	if !ok {
+		return OrderState{}, false
+	}
+
+	return *order, true
+}
+
+// OrderByClientID returns state of the order by clientOrderId.
+func (t *OrderTracker) OrderByClientID(clientOrderID string) (OrderState, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	orderNumber, ok := t.byClientID[clientOrderID]
+	if !ok {
+		return OrderState{}, false
+	}
+
+	order, ok := t.orders[orderNumber]
+	if !ok {
+		return OrderState{}, false
+	}
+
+	return *order, true
+}
+
+// Orders returns states of all tracked orders.
+func (t *OrderTracker) Orders() []OrderState {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	orders := make([]OrderState, 0, len(t.orders))
+	for _, order := range t.orders {
+		orders = append(orders, *order)
+	}
+
+	return orders
+}
+
+// Forget removes the order from tracker.
+func (t *OrderTracker) Forget(orderNumber string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.forget(orderNumber)
+}
+
+// Prune removes final orders which were not updated for longer than age.
+func (t *OrderTracker) Prune(age time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	deadline := time.Now().Add(-age)
+	for orderNumber, order := range t.orders {
+		if order.Status.IsFinal() && order.UpdatedAt.Before(deadline) {
+			t.forget(orderNumber)
+		}
+	}
+}
+
+func (t *OrderTracker) forget(orderNumber string) {
+	order, ok := t.orders[orderNumber]
+	if !ok {
+		return
+	}
+
+	if order.ClientOrderID != "" {
+		delete(t.byClientID, order.ClientOrderID)
+	}
+	delete(t.orders, orderNumber)
+}
+
+// Apply updates order states with account notifications.
+func (t *OrderTracker) Apply(updates []AccountUpdate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, update := range updates {
+		switch data := update.Data.(type) {
+		case Pending:
+			order := t.order(data.OrderNumber, data.ClientOrderID)
+			order.CurrencyPairID = data.CurrencyPairID
+			if data.Market != "" {
+				order.Market = data.Market
+			}
+			order.Side = data.OrderType
+			order.Rate = data.Rate
+			if order.OriginalAmount == 0 {
+				order.OriginalAmount = data.Amount
+				order.Remaining = data.Amount - order.Executed
+			}
+
+			if order.Status == 0 {
+				t.setStatus(order, OrderStatusPending, update.TypeUpdate)
+			}
+
+		case NewOrder:
+			order := t.order(data.OrderNumber, data.ClientOrderID)
+			order.CurrencyPairID = data.CurrencyPairID
+			if data.Market != "" {
+				order.Market = data.Market
+			}
+			order.Side = data.OrderType
+			order.Rate = data.Rate
+			order.OriginalAmount = data.OriginalAmountOrdered
+			order.Remaining = data.Amount
+
+			if order.Executed > amountEpsilon {
+				t.setStatus(order, OrderStatusPartiallyFilled, update.TypeUpdate)
+			} else {
+				t.setStatus(order, OrderStatusOpen, update.TypeUpdate)
+			}
+
+		case Trade:
+			order := t.order(data.OrderNumber, data.ClientOrderID)
+			order.Executed += data.Amount
+			order.executedTotal += data.Rate * data.Amount
+			order.AveragePrice = order.executedTotal / order.Executed
+			order.TotalFee += data.TotalFee
+
+			if order.OriginalAmount > 0 {
+				order.Remaining = order.OriginalAmount - order.Executed - order.Canceled
+				if order.Remaining < amountEpsilon {
+					order.Remaining = 0
+				}
+			}
+
+			if order.OriginalAmount > 0 && order.Remaining == 0 {
+				t.setStatus(order, OrderStatusFilled, update.TypeUpdate)
+			} else {
+				t.setStatus(order, OrderStatusPartiallyFilled, update.TypeUpdate)
+			}
+
+		case OrderUpdate:
+			order := t.order(data.OrderNumber, data.ClientOrderID)
+			order.Remaining = data.NewAmount
+
+			// self-trade leaves the rest of the order on the book
+			_, finished := finishedOrder(data)
+			switch {
+			case data.OrderType == OrderUpdateCanceled:
+				order.Canceled += data.CanceledAmount
+				t.setStatus(order, OrderStatusCanceled, update.TypeUpdate)
+			case !finished:
+				t.setStatus(order, OrderStatusPartiallyFilled, update.TypeUpdate)
+			case data.OrderType == OrderUpdateSelfTrade:
+				t.setStatus(order, OrderStatusSelfTrade, update.TypeUpdate)
+			default:
+				t.setStatus(order, OrderStatusFilled, update.TypeUpdate)
+			}
+
+		case Kill:
+			order := t.order(data.OrderNumber, data.ClientOrderID)
+			order.Remaining = 0
+			t.setStatus(order, OrderStatusKilled, update.TypeUpdate)
+
+		case MarginPositionUpdate:
+			order := t.order(data.OrderNumber, data.ClientOrderID)
+			order.MarginAmount = data.Amount
+			order.UpdatedAt = time.Now()
+		}
+	}
+}
+
+// Returns tracked order, registering it if it is seen for the first time.
+func (t *OrderTracker) order(orderNumber, clientOrderID string) *OrderState {
+	order, ok := t.orders[orderNumber]
+	if !ok {
+		order = &OrderState{OrderNumber: orderNumber}
+		t.orders[orderNumber] = order
+	}
+
+	if clientOrderID != "" && order.ClientOrderID == "" {
+		order.ClientOrderID = clientOrderID
+		t.byClientID[clientOrderID] = orderNumber
+	}
+
+	return order
+}
+
+// Change order status and publish transition.
+// Final states are not left, so late messages don't resurrect an order.
+func (t *OrderTracker) setStatus(order *OrderState, status OrderStatus, cause UpdateType) {
+	order.UpdatedAt = time.Now()
+
+	if order.Status == status || order.Status.IsFinal() {
+		return
+	}
+
+	transition := OrderTransition{
+		From:  order.Status,
+		To:    status,
+		Cause: cause,
+	}
+	order.Status = status
+	transition.Order = *order
+
+	select {
+	case t.transitions <- transition:
+	default:
+		t.dropped++
+	}
+}
@@ -0,0 +1,399 @@
+package poloniex
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TradingAPI is a part of private API implemented by Poloniex and PaperTrader,
+// so strategies can run without sending orders.
+type TradingAPI interface {
+	Buy(market string, price, amount float64) (Buy, error)
+	Sell(market string, price, amount float64) (Sell, error)
+	CancelOrder(orderNumber string) (CancelOrder, error)
+	GetOpenOrders(market string) ([]OpenOrder, error)
+	GetBalances() (map[string]string, error)
+}
+
+var (
+	_ TradingAPI = (*Poloniex)(nil)
+	_ TradingAPI = (*PaperTrader)(nil)
+)
+
+// paperQuote is the best bid and ask of a market.
+type paperQuote struct {
+	bid float64
+	ask float64
+}
+
+// paperOrder is an order resting on the simulated book.
+type paperOrder struct {
+	market         string
+	side           Side
+	rate           float64
+	startingAmount float64
+	amount         float64 // remaining amount
+	date           time.Time
+}
+
+// PaperTrader simulates the private API against live tickers.
+// Orders crossing the best bid or ask are executed at once at the quote price with taker fee,
+// other orders rest until a ticker crosses their price and are executed at the order price with maker fee.
+// Tickers carry no depth, so orders are always executed completely.
+type PaperTrader struct {
+	balances    map[string]float64 // available balances by currency
+	orders      map[string]*paperOrder
+	quotes      map[string]paperQuote
+	makerFee    float64
+	takerFee    float64
+	lastOrderID int64
+	lastTradeID int64
+	account     chan interface{}
+	dropped     uint64 // notifications not sent because the channel was full
+	mu          sync.Mutex
+}
+
+// NewPaperTrader creates paper trader with virtual balances and fee rates, e.g. 0.0009 for 0.09%.
+func NewPaperTrader(balances map[string]float64, makerFee, takerFee float64) *PaperTrader {
+	t := &PaperTrader{
+		balances:    make(map[string]float64),
+		orders:      make(map[string]*paperOrder),
+		quotes:      make(map[string]paperQuote),
+		makerFee:    makerFee,
+		takerFee:    takerFee,
+		lastOrderID: time.Now().Unix(),
+		account:     make(chan interface{}, SUBSBUFFER),
+	}
+
+	for currency, amount := range balances {
+		t.balances[strings.ToUpper(currency)] = amount
+	}
+
+	return t
+}
+
+// Account returns simulated account notifications stream.
+// It carries []AccountUpdate like ws.Subs["ACCOUNT"]. Like the websocket, the trader
+// does not wait for a slow reader; notifications which do not fit are counted by Dropped.
+func (t *PaperTrader) Account() chan interface{} {
+	return t.account
+}
+
+// Dropped returns the number of notifications not sent to the full Account channel.
+func (t *PaperTrader) Dropped() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.dropped
+}
+
+// SyncTickers sets quotes of all markets from GetTickers.
+func (t *PaperTrader) SyncTickers(client *Poloniex) error {
+	tickers, err := client.GetTickers()
+	if err != nil {
+		return err
+	}
+
+	for market, ticker := range tickers {
+		bid, _ := ticker.HighestBid.Float64()
+		ask, _ := ticker.LowestAsk.Float64()
+		t.UpdateQuote(market, bid, ask)
+	}
+
+	return nil
+}
+
+// RunTicker consumes ticker updates, e.g. ws.Subs["TICKER"] after SubscribeTicker,
+// until updates channel is closed.
+func (t *PaperTrader) RunTicker(updates <-chan interface{}) {
+	for update := range updates {
+		if ticker, ok := update.(WSTicker); ok {
+			t.UpdateQuote(ticker.Symbol, ticker.HighestBid, ticker.LowestAsk)
+		}
+	}
+}
+
+// UpdateQuote sets the best bid and ask of the market and executes resting orders crossed by them.
+func (t *PaperTrader) UpdateQuote(market string, bid, ask float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	market = strings.ToUpper(market)
+	t.quotes[market] = paperQuote{bid: bid, ask: ask}
+
+	var updates []AccountUpdate
+	for orderNumber, order := range t.orders {
+		if order.market != market || !crosses(order.side, order.rate, t.quotes[market]) {
+			continue
+		}
+
+		_, fill := t.execute(orderNumber, order, order.rate, t.makerFee)
+		updates = append(updates, fill...)
+	}
+
+	t.emit(updates)
+}
+
+// Buy places simulated buy order.
+func (t *PaperTrader) Buy(market string, price, amount float64) (buy Buy, err error) {
+	return t.place(market, SideBuy, price, amount)
+}
+
+// Sell places simulated sell order.
+func (t *PaperTrader) Sell(market string, price, amount float64) (sell Sell, err error) {
+	buy, err := t.place(market, SideSell, price, amount)
+	return Sell(buy), err
+}
+
+// CancelOrder cancels resting order and releases its funds.
+func (t *PaperTrader) CancelOrder(orderNumber string) (cancelOrder CancelOrder, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.orders[orderNumber]
+	if !ok {
+		return cancelOrder, Error(PaperTradeError, "invalid order number, or you are not the person who placed the order")
+	}
+
+	delete(t.orders, orderNumber)
+
+	currency, reserved := order.reserved(order.amount)
+	t.balances[currency] += reserved
+
+	t.emit([]AccountUpdate{{
+		Data: OrderUpdate{
+			OrderNumber:    orderNumber,
+			NewAmount:      0,
+			OrderType:      OrderUpdateCanceled,
+			CanceledAmount: order.amount,
+		},
+		TypeUpdate: MessageTypeOrderUpdate,
+	}})
+
+	cancelOrder.Success = 1
+	return cancelOrder, nil
+}
+
+// GetOpenOrders returns resting orders of the market.
+func (t *PaperTrader) GetOpenOrders(market string) (openOrders []OpenOrder, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	market = strings.ToUpper(market)
+	openOrders = []OpenOrder{}
+
+	for orderNumber, order := range t.orders {
+		if order.market != market {
+			continue
+		}
+
+		openOrders = append(openOrders, OpenOrder{
+			OrderNumber:    orderNumber,
+			Type:           order.side.String(),
+			Price:          decimal.NewFromFloat(order.rate),
+			StartingAmount: decimal.NewFromFloat(order.startingAmount),
+			Amount:         decimal.NewFromFloat(order.amount),
+			Total:          decimal.NewFromFloat(order.rate * order.amount),
+			Date:           order.date.UTC().Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return openOrders, nil
+}
+
+// GetBalances returns available virtual balances.
+func (t *PaperTrader) GetBalances() (balances map[string]string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	balances = make(map[string]string, len(t.balances))
+	for currency, amount := range t.balances {
+		balances[currency] = strconv.FormatFloat(amount, 'f', 8, 64)
+	}
+
+	return balances, nil
+}
+
+// Place the order, executing it at once if it crosses the quote.
+func (t *PaperTrader) place(market string, side Side, price, amount float64) (buy Buy, err error) {
+	req := OrderRequest{Market: market, Side: side, Price: price, Amount: amount}
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	market = strings.ToUpper(market)
+	order := &paperOrder{
+		market:         market,
+		side:           side,
+		rate:           price,
+		startingAmount: amount,
+		amount:         amount,
+		date:           time.Now(),
+	}
+
+	currency, reserved := order.reserved(amount)
+	if t.balances[currency] < reserved-amountEpsilon {
+		return buy, Error(PaperTradeError, "not enough "+currency)
+	}
+	t.balances[currency] -= reserved
+
+	t.lastOrderID++
+	orderNumber := strconv.FormatInt(t.lastOrderID, 10)
+	buy.OrderNumber = orderNumber
+
+	updates := []AccountUpdate{{
+		Data: Pending{
+			OrderNumber:    orderNumber,
+			CurrencyPairID: t.currencyPairID(market),
+			Market:         market,
+			Rate:           price,
+			Amount:         amount,
+			OrderType:      side,
+			EpochMS:        strconv.FormatInt(order.date.UnixNano()/int64(time.Millisecond), 10),
+		},
+		TypeUpdate: MessageTypePending,
+	}}
+
+	if quote, ok := t.quotes[market]; ok && crosses(side, price, quote) {
+		rate := quote.ask
+		if side == SideSell {
+			rate = quote.bid
+		}
+
+		trade, fill := t.execute(orderNumber, order, rate, t.takerFee)
+		buy.ResultingTrades = append(buy.ResultingTrades, trade)
+		updates = append(updates, fill...)
+	} else {
+		t.orders[orderNumber] = order
+		updates = append(updates, AccountUpdate{
+			Data: NewOrder{
+				CurrencyPairID:        t.currencyPairID(market),
+				Market:                market,
+				OrderNumber:           orderNumber,
+				OrderType:             side,
+				Rate:                  price,
+				Amount:                amount,
+				Date:                  order.date.UTC().Format("2006-01-02 15:04:05"),
+				OriginalAmountOrdered: amount,
+			},
+			TypeUpdate: MessageTypeNewOrder,
+		})
+	}
+
+	t.emit(updates)
+	return buy, nil
+}
+
+// Execute the whole remaining amount of the order at rate, update balances
+// and return resulting trade with account notifications.
+func (t *PaperTrader) execute(orderNumber string, order *paperOrder, rate, feeRate float64) (ResultTrades, []AccountUpdate) {
+	amount := order.amount
+	total := rate * amount
+	base, quote := splitMarket(order.market)
+
+	// funds reserved above the execution price are released
+	currency, reserved := order.reserved(amount)
+
+	var fee float64
+	if order.side == SideBuy {
+		fee = amount * feeRate
+		t.balances[currency] += reserved - total
+		t.balances[quote] += amount - fee
+	} else {
+		fee = total * feeRate
+		t.balances[currency] += reserved - amount
+		t.balances[base] += total - fee
+	}
+
+	delete(t.orders, orderNumber)
+	order.amount = 0
+
+	t.lastTradeID++
+	now := time.Now()
+
+	trade := ResultTrades{
+		Amount:  decimal.NewFromFloat(amount),
+		Date:    now.UTC().Format("2006-01-02 15:04:05"),
+		Rate:    decimal.NewFromFloat(rate),
+		Total:   decimal.NewFromFloat(total),
+		TradeID: decimal.New(t.lastTradeID, 0),
+		Type:    order.side.String(),
+	}
+
+	updates := []AccountUpdate{
+		{
+			Data: Trade{
+				TradeID:       strconv.FormatInt(t.lastTradeID, 10),
+				Rate:          rate,
+				Amount:        amount,
+				FeeMultiplier: feeRate,
+				FundingType:   FundingTypeExchange,
+				OrderNumber:   orderNumber,
+				TotalFee:      fee,
+				Date:          now.UTC(),
+				TradeTotal:    total,
+				EpochMS:       strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10),
+			},
+			TypeUpdate: MessageTypeTrade,
+		},
+		{
+			Data: OrderUpdate{
+				OrderNumber: orderNumber,
+				NewAmount:   0,
+				OrderType:   OrderUpdateFill,
+			},
+			TypeUpdate: MessageTypeOrderUpdate,
+		},
+	}
+
+	return trade, updates
+}
+
+// Returns currency and amount reserved for amount of the order.
+func (o *paperOrder) reserved(amount float64) (currency string, reserved float64) {
+	base, quote := splitMarket(o.market)
+	if o.side == SideBuy {
+		return base, o.rate * amount
+	}
+
+	return quote, amount
+}
+
+// Returns currency pair id from markets registry, empty if markets are not loaded.
+func (t *PaperTrader) currencyPairID(market string) string {
+	id, ok := channelID(market)
+	if !ok {
+		return ""
+	}
+
+	return strconv.Itoa(id)
+}
+
+// Send account notifications to the simulated stream.
+func (t *PaperTrader) emit(updates []AccountUpdate) {
+	if len(updates) == 0 {
+		return
+	}
+
+	select {
+	case t.account <- updates:
+	default:
+		t.dropped++
+	}
+}
+
+// Reports whether the order at price is executed by the quote.
+func crosses(side Side, price float64, quote paperQuote) bool {
+	if side == SideBuy {
+		return quote.ask > 0 && price >= quote.ask
+	}
+
+	return quote.bid > 0 && price <= quote.bid
+}
@@ -73,6 +73,87 @@ func (p *Poloniex) GetAccountBalances() (accounts Accounts, err error) {
 	return
 }
 
+type TransferBalance struct {
+	Success int    `json:"success"`
+	Message string `json:"message"`
+}
+
+// TransferBalance moves funds between exchange, margin and lending wallets.
+func (p *Poloniex) TransferBalance(currency string, amount float64,
+	fromAccount, toAccount Wallet) (transfer TransferBalance, err error) {
+
+	if !fromAccount.isValid() || !toAccount.isValid() || fromAccount == toAccount {
+		return transfer, Error(TransferError, fromAccount, toAccount)
+	}
+
+	parameters := map[string]string{
+		"currency":    strings.ToUpper(currency),
+		"amount":      strconv.FormatFloat(amount, 'f', 8, 64),
+		"fromAccount": fromAccount.String(),
+		"toAccount":   toAccount.String(),
+	}
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("transferBalance", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &transfer)
+	return
+}
+
+type FeeInfo struct {
+	MakerFee        decimal.Decimal `json:"makerFee"`
+	TakerFee        decimal.Decimal `json:"takerFee"`
+	MarginMakerFee  decimal.Decimal `json:"marginMakerFee"`
+	MarginTakerFee  decimal.Decimal `json:"marginTakerFee"`
+	ThirtyDayVolume decimal.Decimal `json:"thirtyDayVolume"`
+	NextTier        decimal.Decimal `json:"nextTier"`
+}
+
+// GetFeeInfo returns current maker and taker fees and 30-day trading volume.
+func (p *Poloniex) GetFeeInfo() (feeInfo FeeInfo, err error) {
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("returnFeeInfo", nil, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &feeInfo)
+	return
+}
+
+// EstimateFee returns expected fee of the order and currency it is charged in.
+// Buy fee is taken from bought currency, sell fee is taken from base currency of the market.
+func (f FeeInfo) EstimateFee(market string, side Side, price, amount float64, maker bool) (fee decimal.Decimal, currency string) {
+	rate := f.TakerFee
+	if maker {
+		rate = f.MakerFee
+	}
+
+	base, quote := splitMarket(market)
+
+	if side == SideBuy {
+		return decimal.NewFromFloat(amount).Mul(rate), quote
+	}
+
+	total := decimal.NewFromFloat(price).Mul(decimal.NewFromFloat(amount))
+	return total.Mul(rate), base
+}
+
 type NewAddress struct {
 	Success  int    `json:"success"`
 	Response string `json:"response"`
@@ -113,6 +194,135 @@ func (p *Poloniex) GenerateNewAddress(currency string) (newAddress NewAddress, e
 	return
 }
 
+type Deposit struct {
+	DepositNumber int64           `json:"depositNumber"`
+	Currency      string          `json:"currency"`
+	Address       string          `json:"address"`
+	PaymentID     string          `json:"paymentID"`
+	Amount        decimal.Decimal `json:"amount"`
+	Confirmations int             `json:"confirmations"`
+	TxID          string          `json:"txid"`
+	Timestamp     int64           `json:"timestamp"`
+	Status        string          `json:"status"`
+}
+
+type Withdrawal struct {
+	WithdrawalNumber int64           `json:"withdrawalNumber"`
+	Currency         string          `json:"currency"`
+	Address          string          `json:"address"`
+	PaymentID        string          `json:"paymentID"`
+	Amount           decimal.Decimal `json:"amount"`
+	Fee              decimal.Decimal `json:"fee"`
+	Timestamp        int64           `json:"timestamp"`
+	Status           string          `json:"status"`
+	TxID             string          `json:"-"`
+	IPAddress        string          `json:"ipAddress"`
+}
+
+type DepositsWithdrawals struct {
+	Deposits    []Deposit    `json:"deposits"`
+	Withdrawals []Withdrawal `json:"withdrawals"`
+}
+
+// GetDepositsWithdrawals returns deposits and withdrawals history within the time range.
+func (p *Poloniex) GetDepositsWithdrawals(start, end time.Time) (history DepositsWithdrawals, err error) {
+	parameters := map[string]string{
+		"start": strconv.FormatInt(start.Unix(), 10),
+		"end":   strconv.FormatInt(end.Unix(), 10),
+	}
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("returnDepositsWithdrawals", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &history)
+	if err != nil {
+		return
+	}
+
+	// withdrawal status is "COMPLETE: <txid>" once it is sent
+	for i, w := range history.Withdrawals {
+		if idx := strings.Index(w.Status, ": "); idx != -1 {
+			history.Withdrawals[i].Status = w.Status[:idx]
+			history.Withdrawals[i].TxID = w.Status[idx+2:]
+		}
+	}
+
+	return
+}
+
+// AllowWithdrawalAddress adds address to the list of withdrawal destinations.
+// Withdraw refuses to send funds to addresses which are not allowed.
+func (p *Poloniex) AllowWithdrawalAddress(currency, address string) {
+	p.withdrawalMu.Lock()
+	defer p.withdrawalMu.Unlock()
+
+	currency = strings.ToUpper(currency)
+
+	if p.withdrawalAddresses == nil {
+		p.withdrawalAddresses = make(map[string]map[string]struct{})
+	}
+	if p.withdrawalAddresses[currency] == nil {
+		p.withdrawalAddresses[currency] = make(map[string]struct{})
+	}
+
+	p.withdrawalAddresses[currency][address] = struct{}{}
+}
+
+func (p *Poloniex) isWithdrawalAllowed(currency, address string) bool {
+	p.withdrawalMu.RLock()
+	defer p.withdrawalMu.RUnlock()
+
+	_, ok := p.withdrawalAddresses[currency][address]
+	return ok
+}
+
+type Withdraw struct {
+	Response string `json:"response"`
+}
+
+// Withdraw sends funds to the allowed address. paymentID is optional.
+func (p *Poloniex) Withdraw(currency string, amount float64, address, paymentID string) (withdraw Withdraw, err error) {
+	currency = strings.ToUpper(currency)
+
+	if !p.isWithdrawalAllowed(currency, address) {
+		return withdraw, Error(WithdrawAddressError, currency, address)
+	}
+
+	parameters := map[string]string{
+		"currency": currency,
+		"amount":   strconv.FormatFloat(amount, 'f', 8, 64),
+		"address":  address,
+	}
+
+	if paymentID != "" {
+		parameters["paymentId"] = paymentID
+	}
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("withdraw", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &withdraw)
+	return
+}
+
 type OpenOrder struct {
 	OrderNumber    string          `json:"orderNumber"`
 	Type           string          `json:"type"`
@@ -193,6 +403,92 @@ func (p *Poloniex) CancelOrder(orderNumber string) (cancelorder CancelOrder, err
 	return
 }
 
+// CancelOrderByClientOrderID cancels the order placed with clientOrderId.
+func (p *Poloniex) CancelOrderByClientOrderID(clientOrderID string) (cancelorder CancelOrder, err error) {
+	if err = validateClientOrderID(clientOrderID); err != nil {
+		return
+	}
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	parameters := map[string]string{"clientOrderId": clientOrderID}
+	go p.tradingRequest("cancelOrder", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(resp, &cancelorder)
+	return
+}
+
+type CancelAllOrders struct {
+	Success      int      `json:"success"`
+	Message      string   `json:"message"`
+	OrderNumbers []string `json:"-"`
+}
+
+// CancelAllOrders cancels all open orders in the market and stops observing them
+// when observer retention is over.
+func (p *Poloniex) CancelAllOrders(market string) (cancelAllOrders CancelAllOrders, err error) {
+	parameters := map[string]string{"currencyPair": strings.ToUpper(market)}
+	return p.cancelAllOrders(parameters)
+}
+
+// CancelAllAccountOrders cancels all open orders in every market and stops observing them
+// when observer retention is over.
+func (p *Poloniex) CancelAllAccountOrders() (cancelAllOrders CancelAllOrders, err error) {
+	return p.cancelAllOrders(nil)
+}
+
+func (p *Poloniex) cancelAllOrders(parameters map[string]string) (cancelAllOrders CancelAllOrders, err error) {
+	var orderNumbers struct {
+		OrderNumbers []json.Number `json:"orderNumbers"`
+	}
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("cancelAllOrders", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(resp, &cancelAllOrders); err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(resp, &orderNumbers); err != nil {
+		return
+	}
+
+	cancelAllOrders.OrderNumbers = make([]string, 0, len(orderNumbers.OrderNumbers))
+	for _, orderNumber := range orderNumbers.OrderNumbers {
+		cancelAllOrders.OrderNumbers = append(cancelAllOrders.OrderNumbers, orderNumber.String())
+		p.finishObserved(orderNumber.String())
+	}
+
+	return
+}
+
+// Stop observing the order. Observers with retention keep it until late trades are reported.
+func (p *Poloniex) finishObserved(orderID string) {
+	if f, ok := p.observer.(interface{ Finish(orderID string) }); ok {
+		f.Finish(orderID)
+		return
+	}
+
+	_ = p.observer.Delete(orderID)
+}
+
 type TradeHistory struct {
 	GlobalTradeID int             `json:"globalTradeId"`
 	TradeID       string          `json:"tradeId"`
@@ -261,6 +557,7 @@ func (p *Poloniex) GetTradesByOrderID(orderNumber string) (ordertrades []OrderTr
 }
 
 type OrderStat struct {
+	OrderNumber    string          `json:"-"`
 	Status         string          `json:"status"`
 	Rate           decimal.Decimal `json:"rate"`
 	Amount         decimal.Decimal `json:"amount"`
@@ -286,13 +583,27 @@ type OrderStat2 struct {
 }
 
 func (p *Poloniex) GetOrderStat(orderNumber string) (orderStat OrderStat, err error) {
+	parameters := map[string]string{"orderNumber": orderNumber}
+	return p.orderStat(parameters)
+}
+
+// GetOrderStatByClientOrderID returns status of the order placed with clientOrderId.
+func (p *Poloniex) GetOrderStatByClientOrderID(clientOrderID string) (orderStat OrderStat, err error) {
+	if err = validateClientOrderID(clientOrderID); err != nil {
+		return
+	}
+
+	parameters := map[string]string{"clientOrderId": clientOrderID}
+	return p.orderStat(parameters)
+}
+
+func (p *Poloniex) orderStat(parameters map[string]string) (orderStat OrderStat, err error) {
 	var check1 OrderStat1
 	var check2 OrderStat2
 
 	respCh := make(chan []byte)
 	errCh := make(chan error)
 
-	parameters := map[string]string{"orderNumber": orderNumber}
 	go p.tradingRequest("returnOrderStatus", parameters, respCh, errCh)
 
 	resp := <-respCh
@@ -319,8 +630,14 @@ func (p *Poloniex) GetOrderStat(orderNumber string) (orderStat OrderStat, err er
 		return
 	}
 	if check2.Success == 1 {
-		orderStat = check2.Result[orderNumber]
-		return
+		for orderNumber, stat := range check2.Result {
+			if parameters["orderNumber"] != "" && parameters["orderNumber"] != orderNumber {
+				continue
+			}
+
+			stat.OrderNumber = orderNumber
+			return stat, nil
+		}
 	}
 
 	return orderStat, errors.New("unexpected result")
@@ -337,59 +654,78 @@ type ResultTrades struct {
 
 type Buy struct {
 	OrderNumber     string `json:"orderNumber"`
+	ClientOrderID   string `json:"-"`
 	ResultingTrades []ResultTrades
 }
 
 func (p *Poloniex) Buy(market string, price, amount float64) (buy Buy, err error) {
-	parameters := map[string]string{
-		"currencyPair": strings.ToUpper(market),
-		"rate":         strconv.FormatFloat(price, 'f', 8, 64),
-		"amount":       strconv.FormatFloat(amount, 'f', 8, 64),
-	}
-
-	respCh := make(chan []byte)
-	errCh := make(chan error)
+	result, err := p.PlaceOrder(OrderRequest{
+		Market: market,
+		Side:   SideBuy,
+		Price:  price,
+		Amount: amount,
+	})
+	return result.Buy, err
+}
 
-	go p.tradingRequest("buy", parameters, respCh, errCh)
+// BuyWithClientOrderID places buy order tagged with clientOrderId.
+// If clientOrderID is empty, a new one is generated with NewClientOrderID.
+func (p *Poloniex) BuyWithClientOrderID(market string, price, amount float64,
+	clientOrderID string) (buy Buy, err error) {
 
-	resp := <-respCh
-	err = <-errCh
-
-	if err != nil {
-		return
+	if clientOrderID == "" {
+		clientOrderID = NewClientOrderID()
 	}
 
-	err = json.Unmarshal(resp, &buy)
-
-	_ = p.observer.Observe("buy", parameters["currencyPair"], buy.OrderNumber)
-
-	return
+	result, err := p.PlaceOrder(OrderRequest{
+		Market:        market,
+		Side:          SideBuy,
+		Price:         price,
+		Amount:        amount,
+		ClientOrderID: clientOrderID,
+	})
+	return result.Buy, err
 }
 
 type Sell Buy
 
 func (p *Poloniex) Sell(market string, price, amount float64) (sell Sell, err error) {
-	parameters := map[string]string{
-		"currencyPair": strings.ToUpper(market),
-		"rate":         strconv.FormatFloat(price, 'f', 8, 64),
-		"amount":       strconv.FormatFloat(amount, 'f', 8, 64),
-	}
+	result, err := p.PlaceOrder(OrderRequest{
+		Market: market,
+		Side:   SideSell,
+		Price:  price,
+		Amount: amount,
+	})
+	return Sell(result.Buy), err
+}
 
-	respCh := make(chan []byte)
-	errCh := make(chan error)
+// SellWithClientOrderID places sell order tagged with clientOrderId.
+// If clientOrderID is empty, a new one is generated with NewClientOrderID.
+func (p *Poloniex) SellWithClientOrderID(market string, price, amount float64,
+	clientOrderID string) (sell Sell, err error) {
 
-	go p.tradingRequest("sell", parameters, respCh, errCh)
+	if clientOrderID == "" {
+		clientOrderID = NewClientOrderID()
+	}
 
-	resp := <-respCh
-	err = <-errCh
+	result, err := p.PlaceOrder(OrderRequest{
+		Market:        market,
+		Side:          SideSell,
+		Price:         price,
+		Amount:        amount,
+		ClientOrderID: clientOrderID,
+	})
+	return Sell(result.Buy), err
+}
 
-	if err != nil {
+// Register placed order in observer. Observer is locked,
+// so ListeningReports doesn't see the order half-registered.
+func (p *Poloniex) observe(side Side, market, orderID string) {
+	if err := p.observer.Lock(); err != nil {
+		logger.WithError(err).Error("can not lock observer")
 		return
 	}
+	defer p.observer.Unlock()
 
-	err = json.Unmarshal(resp, &sell)
-
-	_ = p.observer.Observe("buy", parameters["currencyPair"], sell.OrderNumber)
-
-	return
+	_ = p.observer.Observe(side, market, orderID)
 }
@@ -197,14 +197,20 @@ type PublicTrade struct {
 	Total         decimal.Decimal `json:"total"`
 }
 
+// GetPublicTradeHistory returns recent trades in the market,
+// or trades within the range if both start and end times are given.
 func (p *Poloniex) GetPublicTradeHistory(market string, args ...time.Time) (trades []PublicTrade, err error) {
 	respCh := make(chan []byte)
 	errCh := make(chan error)
 
 	action := fmt.Sprintf("returnTradeHistory&currencyPair=%s", strings.ToUpper(market))
 
-	if len(args) == 2 {
+	switch len(args) {
+	case 0:
+	case 2:
 		action += fmt.Sprintf("&start=%d&end=%d", args[0].Unix(), args[1].Unix())
+	default:
+		return nil, Error(TimeError)
 	}
 
 	go p.publicRequest(action, respCh, errCh)
@@ -231,24 +237,62 @@ type CandleStick struct {
 	WeightedAverage float64 `json:"weightedAverage"`
 }
 
+// Candle periods supported by returnChartData.
+var chartPeriods = map[string]int{
+	"5m":  300,
+	"15m": 900,
+	"30m": 1800,
+	"2h":  7200,
+	"4h":  14400,
+	"1d":  86400,
+}
+
+// ParsePeriod converts period like "1m", "5m", "1h", "1d" or "1w" to duration.
+func ParsePeriod(period string) (time.Duration, error) {
+	if len(period) < 2 {
+		return 0, Error(PeriodError)
+	}
+
+	n, err := strconv.Atoi(period[:len(period)-1])
+	if err != nil || n <= 0 {
+		return 0, Error(PeriodError)
+	}
+
+	var unit time.Duration
+	switch period[len(period)-1] {
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, Error(PeriodError)
+	}
+
+	return time.Duration(n) * unit, nil
+}
+
+// Returns the longest server period which divides interval.
+func chartPeriodFor(interval time.Duration) (period string, ok bool) {
+	best := 0
+	for p, sec := range chartPeriods {
+		if interval%(time.Duration(sec)*time.Second) == 0 && sec > best {
+			best = sec
+			period = p
+		}
+	}
+
+	return period, best > 0
+}
+
 func (p *Poloniex) GetChartData(market string, start, end time.Time, period string) (candles []CandleStick, err error) {
-	var periodSec int
 	var v1, v2 int64
 
-	switch period {
-	case "5m":
-		periodSec = 300
-	case "15m":
-		periodSec = 900
-	case "30m":
-		periodSec = 1800
-	case "2h":
-		periodSec = 7200
-	case "4h":
-		periodSec = 14400
-	case "1d":
-		periodSec = 86400
-	default:
+	periodSec, ok := chartPeriods[period]
+	if !ok {
 		return nil, Error(PeriodError)
 	}
 
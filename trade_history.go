@@ -0,0 +1,296 @@
+package poloniex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default options of trade history iterator.
+const (
+	defaultHistoryWindow       = 24 * time.Hour
+	defaultPrivateHistoryLimit = 10000
+	defaultPublicHistoryLimit  = 1000
+)
+
+// MarketTrade is a trade with the market it belongs to.
+type MarketTrade struct {
+	Market string
+	TradeHistory
+}
+
+// GetAllTradeHistory returns account trades in all markets within the time range.
+func (p *Poloniex) GetAllTradeHistory(start, end time.Time, limit int) (tradehistory map[string][]TradeHistory, err error) {
+	parameters := map[string]string{
+		"currencyPair": "all",
+		"start":        strconv.FormatInt(start.Unix(), 10),
+		"end":          strconv.FormatInt(end.Unix(), 10),
+		"limit":        strconv.Itoa(limit),
+	}
+
+	respCh := make(chan []byte)
+	errCh := make(chan error)
+
+	go p.tradingRequest("returnTradeHistory", parameters, respCh, errCh)
+
+	resp := <-respCh
+	err = <-errCh
+
+	if err != nil {
+		return
+	}
+
+	tradehistory = make(map[string][]TradeHistory)
+
+	// empty result is sent as array
+	if bytes.Equal(bytes.TrimSpace(resp), []byte("[]")) {
+		return
+	}
+
+	err = json.Unmarshal(resp, &tradehistory)
+	return
+}
+
+// TradeHistoryOptions configures trade history iterator.
+type TradeHistoryOptions struct {
+	Window  time.Duration // time range of a single request, 24 hours by default
+	Limit   int           // trades per request, server maximum by default; public history has fixed limit
+	Forward bool          // iterate from the oldest trade, by default from the newest one
+}
+
+// TradeHistoryIterator walks trade history window by window.
+// Every request goes through the client throttle, trades are deduplicated by GlobalTradeID.
+//
+//	it := polo.IterateTradeHistory("all", start, end, poloniex.TradeHistoryOptions{})
+//	for it.Next() {
+//		fmt.Println(it.Trade())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type TradeHistoryIterator struct {
+	fetch   func(start, end time.Time, limit int) ([]MarketTrade, error)
+	start   time.Time
+	end     time.Time
+	cursor  time.Time // edge of the next window
+	options TradeHistoryOptions
+	seen    map[int]struct{}
+	buffer  []MarketTrade
+	trade   MarketTrade
+	err     error
+}
+
+// IterateTradeHistory returns iterator over account trades in the market.
+// Use "all" market to iterate over trades in every market.
+func (p *Poloniex) IterateTradeHistory(market string, start, end time.Time,
+	options TradeHistoryOptions) *TradeHistoryIterator {
+
+	market = strings.ToUpper(market)
+	if options.Limit <= 0 {
+		options.Limit = defaultPrivateHistoryLimit
+	}
+
+	fetch := func(start, end time.Time, limit int) ([]MarketTrade, error) {
+		if market == "ALL" {
+			history, err := p.GetAllTradeHistory(start, end, limit)
+			if err != nil {
+				return nil, err
+			}
+
+			var trades []MarketTrade
+			for m, mTrades := range history {
+				for _, trade := range mTrades {
+					trades = append(trades, MarketTrade{Market: m, TradeHistory: trade})
+				}
+			}
+			return trades, nil
+		}
+
+		history, err := p.GetTradeHistory(market, start, end, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		trades := make([]MarketTrade, 0, len(history))
+		for _, trade := range history {
+			trades = append(trades, MarketTrade{Market: market, TradeHistory: trade})
+		}
+		return trades, nil
+	}
+
+	return newTradeHistoryIterator(fetch, start, end, options)
+}
+
+// IteratePublicTradeHistory returns iterator over public trades in the market.
+// Fee and order number are not set for public trades. The server returns at most
+// 1000 trades per request, other Limit stops the iterator with an error.
+func (p *Poloniex) IteratePublicTradeHistory(market string, start, end time.Time,
+	options TradeHistoryOptions) *TradeHistoryIterator {
+
+	market = strings.ToUpper(market)
+
+	var limitErr error
+	if options.Limit != 0 && options.Limit != defaultPublicHistoryLimit {
+		limitErr = Error(TradeHistoryError, fmt.Sprintf("public history limit is %d", defaultPublicHistoryLimit))
+	}
+	options.Limit = defaultPublicHistoryLimit
+
+	fetch := func(start, end time.Time, _ int) ([]MarketTrade, error) {
+		history, err := p.GetPublicTradeHistory(market, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		trades := make([]MarketTrade, 0, len(history))
+		for _, trade := range history {
+			trades = append(trades, MarketTrade{
+				Market: market,
+				TradeHistory: TradeHistory{
+					GlobalTradeID: int(trade.GlobalTradeID),
+					TradeID:       strconv.FormatUint(trade.TradeID, 10),
+					Date:          trade.Date,
+					Price:         trade.Rate,
+					Amount:        trade.Amount,
+					Total:         trade.Total,
+					Type:          trade.Type,
+				},
+			})
+		}
+		return trades, nil
+	}
+
+	it := newTradeHistoryIterator(fetch, start, end, options)
+	it.err = limitErr
+	return it
+}
+
+func newTradeHistoryIterator(fetch func(start, end time.Time, limit int) ([]MarketTrade, error),
+	start, end time.Time, options TradeHistoryOptions) *TradeHistoryIterator {
+
+	if options.Window <= 0 {
+		options.Window = defaultHistoryWindow
+	}
+
+	it := &TradeHistoryIterator{
+		fetch:   fetch,
+		start:   start,
+		end:     end,
+		options: options,
+		seen:    make(map[int]struct{}),
+	}
+
+	if options.Forward {
+		it.cursor = start
+	} else {
+		it.cursor = end
+	}
+
+	return it
+}
+
+// Next advances to the next trade. It returns false when history is over or an error occurred.
+func (it *TradeHistoryIterator) Next() bool {
+	for len(it.buffer) == 0 {
+		if it.err != nil || it.exhausted() {
+			return false
+		}
+
+		it.err = it.nextWindow()
+	}
+
+	it.trade = it.buffer[0]
+	it.buffer = it.buffer[1:]
+
+	return true
+}
+
+// Trade returns the current trade.
+func (it *TradeHistoryIterator) Trade() MarketTrade {
+	return it.trade
+}
+
+// Err returns the error which stopped iteration.
+func (it *TradeHistoryIterator) Err() error {
+	return it.err
+}
+
+func (it *TradeHistoryIterator) exhausted() bool {
+	if it.options.Forward {
+		return it.cursor.After(it.end)
+	}
+
+	return it.cursor.Before(it.start)
+}
+
+// Fetch all trades of the next window into buffer.
+// Trades fetched before an error are kept in buffer.
+func (it *TradeHistoryIterator) nextWindow() (err error) {
+	var from, to time.Time
+
+	if it.options.Forward {
+		from = it.cursor
+		to = from.Add(it.options.Window)
+		if to.After(it.end) {
+			to = it.end
+		}
+		it.cursor = to.Add(time.Second)
+	} else {
+		to = it.cursor
+		from = to.Add(-it.options.Window)
+		if from.Before(it.start) {
+			from = it.start
+		}
+		it.cursor = from.Add(-time.Second)
+	}
+
+	// Server returns the newest trades of the range, so the upper bound
+	// is moved to the oldest received trade until the window is drained.
+	upper := to
+	for {
+		trades, fetchErr := it.fetch(from, upper, it.options.Limit)
+		if fetchErr != nil {
+			return fetchErr
+		}
+
+		oldest := upper
+		for _, trade := range trades {
+			if date, err := parseStringToTime(trade.Date); err == nil && date.Before(oldest) {
+				oldest = date
+			}
+
+			if _, ok := it.seen[trade.GlobalTradeID]; ok {
+				continue
+			}
+			it.seen[trade.GlobalTradeID] = struct{}{}
+			it.buffer = append(it.buffer, trade)
+		}
+
+		if len(trades) < it.options.Limit {
+			break
+		}
+
+		// ranges have one second resolution, so trades beyond limit within one second can not be fetched
+		if !oldest.Before(upper) {
+			err = Error(TradeHistoryError, fmt.Sprintf("more than %d trades at %v", it.options.Limit, upper))
+			break
+		}
+
+		upper = oldest
+		if upper.Before(from) {
+			break
+		}
+	}
+
+	sort.Slice(it.buffer, func(i, j int) bool {
+		if it.options.Forward {
+			return it.buffer[i].GlobalTradeID < it.buffer[j].GlobalTradeID
+		}
+		return it.buffer[i].GlobalTradeID > it.buffer[j].GlobalTradeID
+	})
+
+	return err
+}
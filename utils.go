@@ -2,7 +2,10 @@ package poloniex
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,8 +28,56 @@ func parseJSONFloatString(data json.RawMessage) (float64, error) {
 	return strconv.ParseFloat(s, 64)
 }
 
+// splitMarket splits market like "BTC_ETH" into base and quote currencies.
+func splitMarket(market string) (base, quote string) {
+	market = strings.ToUpper(market)
+	if idx := strings.Index(market, "_"); idx != -1 {
+		return market[:idx], market[idx+1:]
+	}
+
+	return market, ""
+}
+
+// parseClientOrderID returns clientOrderId from account notification argument.
+// Poloniex sends null when the order was placed without clientOrderId.
+func parseClientOrderID(v interface{}) string {
+	switch id := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', 0, 64)
+	default:
+		return fmt.Sprintf("%v", id)
+	}
+}
+
+// last issued clientOrderId, starts from current time in microseconds
+// so identifiers stay unique across restarts.
+var clientOrderIDSeq = uint64(time.Now().UnixNano() / int64(time.Microsecond))
+
+// NewClientOrderID generates clientOrderId unique within the process.
+func NewClientOrderID() string {
+	return strconv.FormatUint(atomic.AddUint64(&clientOrderIDSeq, 1), 10)
+}
+
+// clientOrderId must be a positive 64-bit integer.
+func validateClientOrderID(clientOrderID string) error {
+	id, err := strconv.ParseInt(clientOrderID, 10, 64)
+	if err != nil || id <= 0 {
+		return Error(ClientOrderIDError, clientOrderID)
+	}
+
+	return nil
+}
+
 func parseStringToTime(t string) (time.Time, error) {
 	// "2021-07-09 03:46:50"
+	if len(t) < 19 {
+		return time.Time{}, Error(WrongTimeFormat)
+	}
+
 	year, err := strconv.Atoi(t[:4])
 	if err != nil {
 		return time.Time{}, Error(WrongTimeFormat)
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -22,14 +23,27 @@ const (
 const (
 	// SUBSBUFFER is subscriptions buffer
 	SUBSBUFFER = 256
+
+	// defaultFillGracePeriod is how long trades of unobserved orders are kept by ListeningReports.
+	defaultFillGracePeriod = 15 * time.Second
+	// fillRetryInterval is how often kept trades are matched again.
+	fillRetryInterval = 100 * time.Millisecond
 )
 
 var (
 	channelsByName = make(map[string]int) // channels map by name
 	channelsByID   = make(map[int]string) // channels map by id
 	marketChannels []int                  // channels list
+	channelsMu     sync.RWMutex           // guards channels maps
+
+	marketsRefreshing int32      // set while markets are refreshed in background
+	marketsRefreshed  time.Time  // last background refresh
+	marketsRefreshMu  sync.Mutex // guards marketsRefreshed
 )
 
+// minMarketsRefreshInterval limits background refreshes caused by unknown currency pair ids.
+const minMarketsRefreshInterval = time.Minute
+
 // WSClient describe single websocket connection.
 type WSClient struct {
 	key        string
@@ -39,6 +53,12 @@ type WSClient struct {
 	wsConn     *websocket.Conn             // websocket connection
 	wsMutex    *sync.Mutex                 // prevent race condition for websocket RW
 	sync.Mutex                             // embedded mutex
+
+	accountHandlers   map[int]func([]AccountUpdate) // account notification handlers by id
+	accountHandlerSeq int                           // last issued handler id
+	handlersMu        sync.RWMutex                  // guards accountHandlers
+
+	fillGracePeriod time.Duration // how long trades of unobserved orders are kept
 }
 
 // NewPublicWSClient creates new web socket public client.
@@ -50,14 +70,28 @@ func NewPublicWSClient() *WSClient {
 }
 
 // NewPrivateWSClient creates new web socket private client.
+// Observers which handle account notifications, e.g. to remove finished orders, are registered automatically.
+// Nil observer means NilObserver.
 func NewPrivateWSClient(observer OrderObserver, key, secret string) *WSClient {
-	return &WSClient{
+	if observer == nil {
+		observer = NewNilObserver()
+	}
+
+	ws := &WSClient{
 		key:      key,
 		secret:   secret,
 		observer: observer,
 		Subs:     make(map[string]chan interface{}),
 		wsMutex:  &sync.Mutex{},
+
+		fillGracePeriod: defaultFillGracePeriod,
+	}
+
+	if applier, ok := observer.(interface{ Apply([]AccountUpdate) }); ok {
+		ws.OnAccountUpdate(applier.Apply)
 	}
+
+	return ws
 }
 
 // Run is connection client to poloniex websocket and start handling messages.
@@ -122,11 +156,16 @@ func setChannelsID() (err error) {
 		return err
 	}
 
+	channelsMu.Lock()
+	defer channelsMu.Unlock()
+
 	for k := range tickers {
 		id := tickers[k].ID
+		if _, ok := channelsByID[id]; !ok {
+			marketChannels = append(marketChannels, id)
+		}
 		channelsByName[k] = id
 		channelsByID[id] = k
-		marketChannels = append(marketChannels, id)
 	}
 
 	channelsByName["TICKER"] = TICKER
@@ -138,6 +177,75 @@ func setChannelsID() (err error) {
 	return
 }
 
+// RefreshMarkets reloads currency pair ids, so newly listed markets can be subscribed
+// and resolved in account notifications.
+func RefreshMarkets() error {
+	return setChannelsID()
+}
+
+// Returns market by currency pair id. Unknown id starts background refresh of markets.
+func marketByID(id int) (string, bool) {
+	channelsMu.RLock()
+	market, ok := channelsByID[id]
+	channelsMu.RUnlock()
+
+	if !ok {
+		refreshMarketsAsync()
+	}
+
+	return market, ok
+}
+
+// Returns channel id by name.
+func channelID(name string) (int, bool) {
+	channelsMu.RLock()
+	defer channelsMu.RUnlock()
+
+	id, ok := channelsByName[name]
+	return id, ok
+}
+
+// Returns channel name by id.
+func channelName(id int) string {
+	channelsMu.RLock()
+	defer channelsMu.RUnlock()
+
+	return channelsByID[id]
+}
+
+func isMarketChannel(id int) bool {
+	channelsMu.RLock()
+	defer channelsMu.RUnlock()
+
+	return intInSlice(id, marketChannels)
+}
+
+// Refresh markets in background, at most once per minMarketsRefreshInterval.
+func refreshMarketsAsync() {
+	marketsRefreshMu.Lock()
+	if time.Since(marketsRefreshed) < minMarketsRefreshInterval {
+		marketsRefreshMu.Unlock()
+		return
+	}
+	marketsRefreshMu.Unlock()
+
+	if !atomic.CompareAndSwapInt32(&marketsRefreshing, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&marketsRefreshing, 0)
+
+		if err := setChannelsID(); err != nil {
+			logger.WithError(err).Error("can not refresh markets")
+		}
+
+		marketsRefreshMu.Lock()
+		marketsRefreshed = time.Now()
+		marketsRefreshMu.Unlock()
+	}()
+}
+
 // Create handler.
 // If the message comes from the channels that are subscribed,
 // it is sent to the chans.
@@ -175,12 +283,16 @@ func (ws *WSClient) wsHandler() error {
 				continue
 			}
 		case chID == ACCOUNT:
-			wsUpdate, err = convertArgsToAccountNotification(args)
+			var updates []AccountUpdate
+			updates, err = convertArgsToAccountNotification(args)
 			if err != nil {
 				logger.WithError(err).Error("can not parse account notification message")
 				continue
 			}
-		case intInSlice(chID, marketChannels):
+
+			ws.dispatchAccountUpdates(updates)
+			wsUpdate = updates
+		case isMarketChannel(chID):
 			wsUpdate, err = convertArgsToMarketUpdate(args)
 			if err != nil {
 				logger.WithError(err).Error("can not parse market update message")
@@ -190,7 +302,7 @@ func (ws *WSClient) wsHandler() error {
 			continue
 		}
 
-		chName := channelsByID[chID]
+		chName := channelName(chID)
 		if ws.Subs[chName] != nil {
 			select {
 			case ws.Subs[chName] <- wsUpdate:
@@ -200,6 +312,43 @@ func (ws *WSClient) wsHandler() error {
 	}
 }
 
+// OnAccountUpdate registers fn to be called with every parsed account notification.
+// Handlers are called synchronously from the websocket reader, so they must not block.
+// The returned function unregisters the handler.
+func (ws *WSClient) OnAccountUpdate(fn func([]AccountUpdate)) (cancel func()) {
+	ws.handlersMu.Lock()
+	defer ws.handlersMu.Unlock()
+
+	if ws.accountHandlers == nil {
+		ws.accountHandlers = make(map[int]func([]AccountUpdate))
+	}
+
+	ws.accountHandlerSeq++
+	id := ws.accountHandlerSeq
+	ws.accountHandlers[id] = fn
+
+	return func() {
+		ws.handlersMu.Lock()
+		delete(ws.accountHandlers, id)
+		ws.handlersMu.Unlock()
+	}
+}
+
+// Pass account notification to every registered handler.
+// Handlers are called without the lock, so they may register or cancel handlers.
+func (ws *WSClient) dispatchAccountUpdates(updates []AccountUpdate) {
+	ws.handlersMu.RLock()
+	handlers := make([]func([]AccountUpdate), 0, len(ws.accountHandlers))
+	for _, fn := range ws.accountHandlers {
+		handlers = append(handlers, fn)
+	}
+	ws.handlersMu.RUnlock()
+
+	for _, fn := range handlers {
+		fn(updates)
+	}
+}
+
 // sub-function for subscription.
 func (ws *WSClient) subscribe(chID int, chName string) (err error) {
 	ws.Lock()
@@ -264,15 +413,14 @@ func (ws *WSClient) sign(formData string) (signature string, err error) {
 	return
 }
 
+// Close closes connection. Observer is locked, so the connection is not closed while trades are matched.
 func (ws *WSClient) Close() error {
-	for {
-		if ws.observer != nil {
-			if err := ws.observer.Lock(); err != nil {
-				continue
-			}
-		}
-		if err := ws.wsConn.Close(); err != nil {
+	if ws.observer != nil {
+		if err := ws.observer.Lock(); err != nil {
 			return err
 		}
+		defer ws.observer.Unlock()
 	}
+
+	return ws.wsConn.Close()
 }
@@ -3,8 +3,11 @@ package poloniex
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -83,10 +86,12 @@ func (ws *WSClient) subscribeToAccountNotification(chID int, chName string) (err
 // AccountUpdate represent a single message on an account.
 type AccountUpdate struct {
 	Data       interface{}
-	TypeUpdate string `json:"type"`
+	TypeUpdate UpdateType `json:"type"`
 }
 
 // ListeningReports make subscription to account executed orders notification.
+// Trades of orders which are not observed yet, e.g. because REST response of Buy
+// arrived after the trade, are kept for the fill grace period and sent once the order is observed.
 func (ws *WSClient) ListeningReports() (ch chan Fill, err error) {
 	if _, isClientSubscribedToAccountNotification := ws.Subs["ACCOUNT"]; !isClientSubscribedToAccountNotification {
 		if err := ws.subscribeToAccountNotification(ACCOUNT, "ACCOUNT"); err != nil {
@@ -96,40 +101,287 @@ func (ws *WSClient) ListeningReports() (ch chan Fill, err error) {
 
 	ch = make(chan Fill, SUBSBUFFER)
 
-	go func(ch chan Fill) {
-		for updates := range ws.Subs["ACCOUNT"] {
-			for _, msg := range updates.([]AccountUpdate) {
-				switch msg.TypeUpdate {
-				case "Trade":
-					trade := msg.Data.(Trade)
-					if ws.observer.IsObservable(trade.OrderNumber) {
-						servObj, err := ws.observer.Items(trade.OrderNumber)
-						if err != nil {
-							return
-						}
+	go func(ch chan Fill, updatesCh chan interface{}, grace time.Duration) {
+		ticker := time.NewTicker(fillRetryInterval)
+		defer ticker.Stop()
 
-						f := Fill{
-							trade.OrderNumber,
-							trade.TradeID,
-							servObj.symbol,
-							trade.Rate,
-							trade.Amount,
-							servObj.side,
-							trade.Date,
-						}
+		var pending []pendingTrade
+		orders := newFillOrders()
+
+		for {
+			select {
+			case updates, ok := <-updatesCh:
+				if !ok {
+					return
+				}
 
-						ch <- f
+				for _, msg := range updates.([]AccountUpdate) {
+					switch data := msg.Data.(type) {
+					case Pending:
+						orders.seen(data.OrderNumber, data.OrderType, false)
+					case NewOrder:
+						orders.seen(data.OrderNumber, data.OrderType, true)
+					case Trade:
+						pending = append(pending, orders.pendingTrade(data))
+					default:
+						if orderNumber, ok := finishedOrder(msg.Data); ok {
+							orders.finished.add(orderNumber, time.Now())
+						}
 					}
-				default:
+				}
+			case <-ticker.C:
+				orders.prune(grace)
+
+				if len(pending) == 0 {
 					continue
 				}
 			}
+
+			var fills []Fill
+			pending, fills = ws.matchTrades(pending, grace)
+
+			for _, f := range fills {
+				ch <- f
+			}
 		}
-	}(ch)
+	}(ch, ws.Subs["ACCOUNT"], ws.fillGracePeriod)
 
 	return ch, nil
 }
 
+// SetFillGracePeriod sets how long ListeningReports keeps trades of orders which are not observed.
+// It must be called before ListeningReports.
+func (ws *WSClient) SetFillGracePeriod(grace time.Duration) {
+	ws.fillGracePeriod = grace
+}
+
+// pendingTrade is a trade waiting for its order to be observed.
+type pendingTrade struct {
+	trade     Trade
+	side      Side // zero if the order was not seen in "p" or "n" messages
+	liquidity Liquidity
+	received  time.Time
+}
+
+// fillOrder is what ListeningReports knows about an order from account notifications.
+type fillOrder struct {
+	side    Side
+	resting bool // "n" message was received, so the order is on the book
+}
+
+// fillOrders keeps orders seen by ListeningReports by order number.
+type fillOrders struct {
+	orders   map[string]*fillOrder
+	finished finishedOrders
+}
+
+func newFillOrders() *fillOrders {
+	return &fillOrders{
+		orders:   make(map[string]*fillOrder),
+		finished: make(finishedOrders),
+	}
+}
+
+// Remember side of the order, resting orders make trades as makers.
+func (o *fillOrders) seen(orderNumber string, side Side, resting bool) {
+	order, ok := o.orders[orderNumber]
+	if !ok {
+		order = &fillOrder{}
+		o.orders[orderNumber] = order
+	}
+
+	order.side = side
+	order.resting = order.resting || resting
+}
+
+// Returns trade with side and liquidity role known at the moment of the trade.
+// Trade of the order placed after subscription but not on the book yet is a taker trade.
+func (o *fillOrders) pendingTrade(trade Trade) pendingTrade {
+	p := pendingTrade{
+		trade:    trade,
+		received: time.Now(),
+	}
+
+	if order, ok := o.orders[trade.OrderNumber]; ok {
+		p.side = order.side
+		p.liquidity = LiquidityTaker
+		if order.resting {
+			p.liquidity = LiquidityMaker
+		}
+	}
+
+	return p
+}
+
+// Forget orders finished earlier than grace ago.
+func (o *fillOrders) prune(grace time.Duration) {
+	for _, orderNumber := range o.finished.expire(time.Now().Add(-grace)) {
+		delete(o.orders, orderNumber)
+	}
+}
+
+// Convert trades of observed orders to fills. Unmatched trades are returned
+// until they are older than grace. Observer is locked, so orders are matched
+// either before or after registration by REST client, never during it.
+func (ws *WSClient) matchTrades(pending []pendingTrade, grace time.Duration) (rest []pendingTrade, fills []Fill) {
+	if err := ws.observer.Lock(); err != nil {
+		return pending, nil
+	}
+	defer ws.observer.Unlock()
+
+	deadline := time.Now().Add(-grace)
+	rest = pending[:0]
+
+	for _, p := range pending {
+		servObj, err := ws.observer.Items(p.trade.OrderNumber)
+		if err != nil {
+			if p.received.After(deadline) {
+				rest = append(rest, p)
+			}
+			continue
+		}
+
+		fills = append(fills, newFill(p, servObj))
+	}
+
+	return rest, fills
+}
+
+// Build fill from trade and observed order. Side sent by the server takes precedence over observed one.
+func newFill(p pendingTrade, order ObservedOrder) Fill {
+	side := p.side
+	if side == 0 {
+		side = order.Side
+	}
+
+	filledAt := p.trade.Date
+	if ms, err := strconv.ParseInt(p.trade.EpochMS, 10, 64); err == nil {
+		filledAt = time.Unix(0, ms*int64(time.Millisecond)).UTC()
+	}
+
+	base, quote := splitMarket(order.Symbol)
+	feeCurrency := base
+	if side == SideBuy {
+		feeCurrency = quote
+	}
+
+	return Fill{
+		OrderID:       p.trade.OrderNumber,
+		TradeID:       p.trade.TradeID,
+		Symbol:        order.Symbol,
+		Price:         p.trade.Rate,
+		Size:          p.trade.Amount,
+		Side:          side,
+		FilledAt:      filledAt,
+		ClientOrderID: p.trade.ClientOrderID,
+		Total:         p.trade.TradeTotal,
+		Fee:           p.trade.TotalFee,
+		FeeMultiplier: p.trade.FeeMultiplier,
+		FeeCurrency:   feeCurrency,
+		FundingType:   p.trade.FundingType,
+		Liquidity:     p.liquidity,
+	}
+}
+
+// TransferReconciliation checks that "b" balance updates arrived
+// for both wallets of a balance transfer.
+type TransferReconciliation struct {
+	currencyID string
+	pending    map[Wallet]float64 // expected balance change by wallet
+	done       chan struct{}
+	cancel     func()
+	mu         sync.Mutex
+}
+
+// ExpectTransfer starts waiting for balance updates of the transfer.
+// It must be called before TransferBalance with the same arguments, because updates may arrive
+// before the response. Currency id sent in balance updates is resolved with GetCurrencies.
+func (ws *WSClient) ExpectTransfer(currency string, amount float64,
+	fromAccount, toAccount Wallet) (*TransferReconciliation, error) {
+
+	if !fromAccount.isValid() || !toAccount.isValid() || fromAccount == toAccount {
+		return nil, Error(TransferError, fromAccount, toAccount)
+	}
+
+	currencies, err := NewPublicClient().GetCurrencies()
+	if err != nil {
+		return nil, err
+	}
+
+	info, ok := currencies[strings.ToUpper(currency)]
+	if !ok {
+		return nil, Error(TransferError, "unknown currency "+currency)
+	}
+
+	if _, ok := ws.Subs["ACCOUNT"]; !ok {
+		if err := ws.SubscribeAccount(); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &TransferReconciliation{
+		currencyID: strconv.Itoa(info.ID),
+		pending: map[Wallet]float64{
+			fromAccount: -amount,
+			toAccount:   amount,
+		},
+		done: make(chan struct{}),
+	}
+	r.cancel = ws.OnAccountUpdate(r.apply)
+
+	return r, nil
+}
+
+// Wait blocks until both balance updates arrived or timeout expired.
+func (r *TransferReconciliation) Wait(timeout time.Duration) error {
+	defer r.cancel()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// updates may have arrived right after the timeout
+	if len(r.pending) == 0 {
+		return nil
+	}
+
+	missing := make([]string, 0, len(r.pending))
+	for wallet := range r.pending {
+		missing = append(missing, wallet.String())
+	}
+
+	return Error(TransferNotConfirmed, missing)
+}
+
+func (r *TransferReconciliation) apply(updates []AccountUpdate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) == 0 {
+		return
+	}
+
+	for _, update := range updates {
+		balance, ok := update.Data.(BalanceUpdate)
+		if !ok || balance.CurrencyID != r.currencyID {
+			continue
+		}
+
+		if expected, ok := r.pending[balance.Wallet]; ok && math.Abs(balance.Amount-expected) < amountEpsilon {
+			delete(r.pending, balance.Wallet)
+		}
+	}
+
+	if len(r.pending) == 0 {
+		close(r.done)
+	}
+}
+
 func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate, err error) {
 	res = make([]AccountUpdate, len(args))
 	for i, val := range args {
@@ -151,6 +403,7 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 				return nil, Error(WSAccountNotification, "pending.CurrencyPairID")
 			}
 			pending.CurrencyPairID = fmt.Sprintf("%.0f", currencyPairID)
+			pending.Market, _ = marketByID(int(currencyPairID))
 
 			rate, ok := vals[3].(string)
 			if !ok {
@@ -178,14 +431,14 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 
 			switch orderType {
 			case OrderTypeBuy:
-				pending.OrderType = OrderTypeBuyValue
+				pending.OrderType = SideBuy
 			case OrderTypeSell:
-				pending.OrderType = OrderTypeSellValue
+				pending.OrderType = SideSell
 			default:
 				return nil, Error(WSWrongOrderType, "pending.OrderType")
 			}
 
-			pending.ClientOrderID = fmt.Sprintf("%v", vals[6])
+			pending.ClientOrderID = parseClientOrderID(vals[6])
 
 			pending.EpochMS, ok = vals[7].(string)
 			if !ok {
@@ -216,16 +469,16 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 
 			switch orderType {
 			case OrderTypeFill:
-				orderUpdate.OrderType = "fill"
+				orderUpdate.OrderType = OrderUpdateFill
 			case OrderTypeSelfTrade:
-				orderUpdate.OrderType = "self-trade"
+				orderUpdate.OrderType = OrderUpdateSelfTrade
 			case OrderTypeCanceled:
-				orderUpdate.OrderType = "canceled"
+				orderUpdate.OrderType = OrderUpdateCanceled
 			default:
 				return nil, Error(WSWrongOrderType, "orderUpdate.OrderType")
 			}
 
-			orderUpdate.ClientOrderID = fmt.Sprintf("%v", vals[4])
+			orderUpdate.ClientOrderID = parseClientOrderID(vals[4])
 			orderUpdate.CanceledAmount, err = strconv.ParseFloat(vals[5].(string), 64)
 			if err != nil {
 				return nil, Error(WSAccountNotification, "orderUpdate.CanceledAmount")
@@ -261,7 +514,11 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 				return
 			}
 
-			trade.FundingType = fmt.Sprintf("%v", vals[5])
+			fundingType, err := strconv.Atoi(fmt.Sprintf("%v", vals[5]))
+			if err != nil {
+				return nil, Error(WSAccountNotification, "trade.FundingType")
+			}
+			trade.FundingType = FundingType(fundingType)
 
 			orderNumber, ok := vals[6].(float64)
 			if !ok {
@@ -280,7 +537,7 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 			}
 			trade.Date = date
 
-			trade.ClientOrderID = fmt.Sprintf("%v", vals[9])
+			trade.ClientOrderID = parseClientOrderID(vals[9])
 
 			trade.TradeTotal, err = strconv.ParseFloat(vals[10].(string), 64)
 			if err != nil {
@@ -301,14 +558,13 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 			}
 			balance.CurrencyID = fmt.Sprintf("%0.f", currencyID)
 
-			balance.Wallet = fmt.Sprintf("%v", vals[2])
 			switch vals[2].(string) {
 			case WalletTypeExchange:
-				balance.Wallet = "exchange"
+				balance.Wallet = WalletExchange
 			case WalletTypeMargin:
-				balance.Wallet = "margin"
+				balance.Wallet = WalletMargin
 			case WalletTypeLending:
-				balance.Wallet = "lending"
+				balance.Wallet = WalletLending
 			default:
 				return nil, Error(WSAccountNotification, "unknown balance.Wallet type")
 			}
@@ -329,7 +585,12 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 		case "n":
 			var order NewOrder
 
-			order.CurrencyPairID = fmt.Sprintf("%v", vals[1])
+			currencyPairID, ok := vals[1].(float64)
+			if !ok {
+				return nil, Error(WSAccountNotification, "order.CurrencyPairID")
+			}
+			order.CurrencyPairID = fmt.Sprintf("%.0f", currencyPairID)
+			order.Market, _ = marketByID(int(currencyPairID))
 
 			orderNumber, ok := vals[2].(float64)
 			if !ok {
@@ -339,9 +600,9 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 
 			switch vals[3].(float64) {
 			case OrderTypeBuy:
-				order.OrderType = OrderTypeBuyValue
+				order.OrderType = SideBuy
 			case OrderTypeSell:
-				order.OrderType = OrderTypeSellValue
+				order.OrderType = SideSell
 			default:
 				return nil, Error(WSWrongOrderType, "pending.OrderType")
 			}
@@ -363,7 +624,7 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 				return nil, Error(WSAccountNotification, "order.OriginalAmountOrdered")
 			}
 
-			order.ClientOrderID = fmt.Sprintf("%v", vals[8])
+			order.ClientOrderID = parseClientOrderID(vals[8])
 
 			accountUpdate.TypeUpdate = MessageTypeNewOrder
 			accountUpdate.Data = order
@@ -384,7 +645,7 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 				return nil, Error(WSAccountNotification, "mpu.Amount")
 			}
 
-			mpu.ClientOrderID = fmt.Sprintf("%v", vals[4])
+			mpu.ClientOrderID = parseClientOrderID(vals[4])
 
 			accountUpdate.TypeUpdate = MessageTypeMargin
 			accountUpdate.Data = mpu
@@ -398,7 +659,7 @@ func convertArgsToAccountNotification(args []interface{}) (res []AccountUpdate,
 			}
 			kill.OrderNumber = fmt.Sprintf("%.0f", orderNumber)
 
-			kill.ClientOrderID = fmt.Sprintf("%v", vals[2])
+			kill.ClientOrderID = parseClientOrderID(vals[2])
 
 			accountUpdate.TypeUpdate = MessageTypeKill
 			accountUpdate.Data = kill
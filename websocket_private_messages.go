@@ -14,14 +14,6 @@ const (
 	OrderTypeSelfTrade = "s"
 	OrderTypeCanceled  = "c"
 
-	MessageTypePending     = "Pending"
-	MessageTypeOrderUpdate = "OrderUpdate"
-	MessageTypeTrade       = "Trade"
-	MessageTypeBalance     = "BalanceUpdate"
-	MessageTypeNewOrder    = "NewOrder"
-	MessageTypeMargin      = "MarginPositionUpdate"
-	MessageTypeKill        = "Kill"
-
 	WalletTypeExchange = "e"
 	WalletTypeMargin   = "m"
 	WalletTypeLending  = "l"
@@ -32,9 +24,10 @@ const (
 type Pending struct {
 	OrderNumber    string
 	CurrencyPairID string
+	Market         string // e.g. "BTC_ETH", empty if currency pair id is unknown
 	Rate           float64
 	Amount         float64
-	OrderType      string
+	OrderType      Side
 	ClientOrderID  string
 	EpochMS        string
 }
@@ -44,7 +37,7 @@ type Pending struct {
 // The wallet can be e (exchange), m (margin), or l (lending).
 type BalanceUpdate struct {
 	CurrencyID string
-	Wallet     string
+	Wallet     Wallet
 	Amount     float64
 	Balance    float64
 }
@@ -54,8 +47,9 @@ type BalanceUpdate struct {
 // OrderType type can either be 0 (sell) or 1 (buy)
 type NewOrder struct {
 	CurrencyPairID        string
+	Market                string // e.g. "BTC_ETH", empty if currency pair id is unknown
 	OrderNumber           string
-	OrderType             string
+	OrderType             Side
 	Rate                  float64
 	Amount                float64
 	Date                  string
@@ -64,11 +58,11 @@ type NewOrder struct {
 }
 
 // OrderUpdate represent "o" messages.
-// OrderType is one of: f, s, or c, corresponding to a fill, self-trade, or canceled order,
+// OrderType is one of: f, s, or c, corresponding to a fill, self-trade, or canceled order.
 type OrderUpdate struct {
 	OrderNumber    string
 	NewAmount      float64
-	OrderType      string
+	OrderType      OrderUpdateKind
 	ClientOrderID  string
 	CanceledAmount float64
 }
@@ -85,17 +79,17 @@ type MarginPositionUpdate struct {
 // The funding type represents the funding used for the trade,
 // which may be 0 (exchange wallet), 1 (borrowed funds), 2 (margin funds), or 3 (lending funds).
 type Trade struct {
-	TradeID       string    `json:"tradeID"`
-	Rate          float64   `json:"rate"`
-	Amount        float64   `json:"amount"`
-	FeeMultiplier float64   `json:"feeMultiplier"`
-	FundingType   string    `json:"fundingType"`
-	OrderNumber   string    `json:"orderNumber"`
-	TotalFee      float64   `json:"totalFee"`
-	Date          time.Time `json:"date"`
-	ClientOrderID string    `json:"clientOrderID"`
-	TradeTotal    float64   `json:"tradeTotal"`
-	EpochMS       string    `json:"epochMS"`
+	TradeID       string      `json:"tradeID"`
+	Rate          float64     `json:"rate"`
+	Amount        float64     `json:"amount"`
+	FeeMultiplier float64     `json:"feeMultiplier"`
+	FundingType   FundingType `json:"fundingType"`
+	OrderNumber   string      `json:"orderNumber"`
+	TotalFee      float64     `json:"totalFee"`
+	Date          time.Time   `json:"date"`
+	ClientOrderID string      `json:"clientOrderID"`
+	TradeTotal    float64     `json:"tradeTotal"`
+	EpochMS       string      `json:"epochMS"`
 }
 
 // Kill represent "k" messages which indicating that an API order has been killed,
@@ -106,12 +100,21 @@ type Kill struct {
 	ClientOrderID string
 }
 
+// Fill is a trade of observed order sent by ListeningReports.
 type Fill struct {
 	OrderID  string
 	TradeID  string
 	Symbol   string
 	Price    float64
 	Size     float64
-	Side     string
-	FilledAt time.Time
+	Side     Side
+	FilledAt time.Time // with millisecond precision when the server sends it
+
+	ClientOrderID string
+	Total         float64 // trade total in base currency
+	Fee           float64 // fee charged for the trade
+	FeeMultiplier float64
+	FeeCurrency   string
+	FundingType   FundingType
+	Liquidity     Liquidity // unknown if the order was placed before subscription
 }
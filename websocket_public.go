@@ -2,8 +2,10 @@ package poloniex
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // subscription and unsubscription
@@ -24,7 +26,7 @@ func (s subscription) toJSON() ([]byte, bool) {
 // MarketUpdate is for market update.
 type MarketUpdate struct {
 	Data       interface{}
-	TypeUpdate string `json:"type"`
+	TypeUpdate UpdateType `json:"type"`
 }
 
 // SubscribeTicker subscribes to ticker channel.
@@ -43,7 +45,7 @@ func (ws *WSClient) UnsubscribeTicker() error {
 // It returns nil if successful.
 func (ws *WSClient) SubscribeMarket(chName string) error {
 	chName = strings.ToUpper(chName)
-	chID, ok := channelsByName[chName]
+	chID, ok := channelID(chName)
 	if !ok {
 		return Error(ChannelError, chName)
 	}
@@ -55,7 +57,7 @@ func (ws *WSClient) SubscribeMarket(chName string) error {
 // It returns nil if successful.
 func (ws *WSClient) UnsubscribeMarket(chName string) error {
 	chName = strings.ToUpper(chName)
-	_, ok := channelsByName[chName]
+	_, ok := channelID(chName)
 	if !ok {
 		return Error(ChannelError, chName)
 	}
@@ -65,7 +67,7 @@ func (ws *WSClient) UnsubscribeMarket(chName string) error {
 
 // Convert ticker update arguments and fill wsTicker.
 func convertArgsToTicker(args []interface{}) (wsTicker WSTicker, err error) {
-	wsTicker.Symbol = channelsByID[int(args[0].(float64))]
+	wsTicker.Symbol = channelName(int(args[0].(float64)))
 
 	wsTicker.Last, err = strconv.ParseFloat(args[1].(string), 64)
 	if err != nil {
@@ -159,16 +161,16 @@ func convertArgsToMarketUpdate(args []interface{}) (res []MarketUpdate, err erro
 				orderDepth.OrderBook.Asks = append(orderDepth.OrderBook.Asks, book)
 			}
 
-			marketUpdate.TypeUpdate = "OrderDepth"
+			marketUpdate.TypeUpdate = MessageTypeOrderDepth
 			marketUpdate.Data = orderDepth
 
 		case "o":
 			var orderDataField WSOrderBook
 
 			if vals[3].(string) == "0.00000000" {
-				marketUpdate.TypeUpdate = "OrderBookRemove"
+				marketUpdate.TypeUpdate = MessageTypeOrderBookRemove
 			} else {
-				marketUpdate.TypeUpdate = "OrderBookModify"
+				marketUpdate.TypeUpdate = MessageTypeOrderBookModify
 			}
 
 			if vals[1].(float64) == 1 {
@@ -201,9 +203,9 @@ func convertArgsToMarketUpdate(args []interface{}) (res []MarketUpdate, err erro
 			}
 
 			if vals[2].(float64) == 1 {
-				tradeDataField.TypeOrder = "buy"
+				tradeDataField.TypeOrder = SideBuy
 			} else {
-				tradeDataField.TypeOrder = "sell"
+				tradeDataField.TypeOrder = SideSell
 			}
 
 			tradeDataField.Rate, err = strconv.ParseFloat(vals[3].(string), 64)
@@ -218,9 +220,22 @@ func convertArgsToMarketUpdate(args []interface{}) (res []MarketUpdate, err erro
 				return
 			}
 
-			tradeDataField.Total = vals[5].(float64)
+			tradeDataField.Total = tradeDataField.Rate * tradeDataField.Amount
 
-			marketUpdate.TypeUpdate = "NewTrade"
+			timestamp, ok := vals[5].(float64)
+			if !ok {
+				err = Error(NewTradeError, "Date")
+				return
+			}
+			tradeDataField.Date = time.Unix(int64(timestamp), 0).UTC()
+
+			if len(vals) > 6 {
+				if epochMS, e := strconv.ParseInt(fmt.Sprintf("%v", vals[6]), 10, 64); e == nil {
+					tradeDataField.Date = time.Unix(0, epochMS*int64(time.Millisecond)).UTC()
+				}
+			}
+
+			marketUpdate.TypeUpdate = MessageTypeNewTrade
 			marketUpdate.Data = tradeDataField
 		}
 		res[i] = marketUpdate
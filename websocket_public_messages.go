@@ -1,5 +1,7 @@
 package poloniex
 
+import "time"
+
 // WSTicker is for ticker update.
 type WSTicker struct {
 	Symbol        string  `json:"symbol"`
@@ -40,10 +42,12 @@ type WSOrderBookRemove struct {
 }
 
 // NewTrade - "t" messages.
+// ["t", "<trade id>", <1 for buy 0 for sell>, "<price>", "<size>", <timestamp>, "<epoch_ms>"]
 type NewTrade struct {
-	TradeID   int64   `json:"tradeID,string"`
-	Rate      float64 `json:"rate,string"`
-	Amount    float64 `json:"amount,string"`
-	Total     float64 `json:"total,string"`
-	TypeOrder string  `json:"type"`
+	TradeID   int64     `json:"tradeID,string"`
+	Rate      float64   `json:"rate,string"`
+	Amount    float64   `json:"amount,string"`
+	Total     float64   `json:"total,string"`
+	TypeOrder Side      `json:"type"`
+	Date      time.Time `json:"date"`
 }